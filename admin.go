@@ -0,0 +1,286 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxAdminRequestBody is the largest request body an AdminHandler will read.
+const maxAdminRequestBody = 64 * 1024
+
+// ErrUnauthenticated may be returned, or wrapped, by an Authorizer to indicate that a
+// request carried no valid credentials. If the Authorizer is also a Challenger, an
+// AdminHandler responds to such requests with http.StatusUnauthorized and the
+// Authorizer's challenge rather than http.StatusForbidden.
+var ErrUnauthenticated = errors.New("the request is not authenticated")
+
+// Authorizer is a strategy for deciding whether a request may use an AdminHandler.
+type Authorizer interface {
+	// Authorize returns nil if the request is allowed, or an error describing why it
+	// is not allowed.
+	Authorize(*http.Request) error
+}
+
+// AuthorizerFunc is a closure type that implements Authorizer.
+type AuthorizerFunc func(*http.Request) error
+
+// Authorize invokes this closure.
+func (af AuthorizerFunc) Authorize(request *http.Request) error {
+	return af(request)
+}
+
+// Challenger may be implemented by an Authorizer to supply the challenge sent in the
+// WWW-Authenticate header of an http.StatusUnauthorized response, e.g. `Bearer realm="admin"`.
+// Since such responses must include a challenge, an AdminHandler responds to unauthenticated
+// requests with http.StatusForbidden when its Authorizer is not a Challenger or supplies
+// an empty challenge.
+type Challenger interface {
+	// Challenge returns the challenge for an unauthenticated request.
+	Challenge(*http.Request) string
+}
+
+// AdminHandlerOption is a configurable option for customizing an AdminHandler.
+type AdminHandlerOption interface {
+	apply(*AdminHandler) error
+}
+
+type adminHandlerOptionFunc func(*AdminHandler) error
+
+func (f adminHandlerOptionFunc) apply(ah *AdminHandler) error { return f(ah) }
+
+// WithAuthorizer sets the strategy that decides which requests an AdminHandler allows.
+// This option is required.
+func WithAuthorizer(a Authorizer) AdminHandlerOption {
+	return adminHandlerOptionFunc(func(ah *AdminHandler) error {
+		ah.authorizer = a
+		return nil
+	})
+}
+
+// StatusRequest is the body of a request to update a subsystem's status.
+type StatusRequest struct {
+	// Status is the new status of the subsystem. If unset, StatusGood is used.
+	Status Status `json:"status"`
+
+	// Error is the optional error text to record with the update.
+	Error string `json:"error,omitempty"`
+}
+
+// OverrideRequest is the body of a request to set a subsystem's override.
+type OverrideRequest struct {
+	// Status is the effective status to force onto the subsystem. This field is
+	// ignored when Exclude is set.
+	Status Status `json:"status"`
+
+	// Exclude indicates that the subsystem should be excluded from aggregation
+	// rather than having its status forced.
+	Exclude bool `json:"exclude"`
+
+	// Reason is the optional, human-readable reason for the override.
+	Reason string `json:"reason,omitempty"`
+
+	// Until is the optional expiry of the override. If unset, the override lasts
+	// until it is cleared.
+	Until time.Time `json:"until,omitempty"`
+}
+
+// AdminHandler is an HTTP handler that allows operators to change the subsystems
+// of a Monitor. Every request must be allowed by the configured Authorizer.
+//
+// Each route is relative to where the handler is mounted, so an AdminHandler is
+// typically mounted under a prefix with http.StripPrefix:
+//
+//	PUT or POST /{name}/status     updates the subsystem using a StatusRequest body
+//	POST        /{name}/probe      runs the subsystem's Probe immediately
+//	POST        /{name}/pause      pauses the subsystem
+//	POST        /{name}/resume     resumes the subsystem
+//	PUT or POST /{name}/override   sets an override using an OverrideRequest body
+//	DELETE      /{name}/override   clears any manual override
+//
+// A successful request responds with the subsystem's resulting Subsystem snapshot
+// as JSON.
+type AdminHandler struct {
+	monitor    *Monitor
+	authorizer Authorizer
+	mux        *http.ServeMux
+}
+
+// NewAdminHandler constructs an AdminHandler for the given Monitor using the
+// supplied set of options.
+func NewAdminHandler(m *Monitor, opts ...AdminHandlerOption) (*AdminHandler, error) {
+	ah := &AdminHandler{
+		monitor: m,
+		mux:     http.NewServeMux(),
+	}
+
+	for _, o := range opts {
+		if err := o.apply(ah); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case ah.monitor == nil:
+		return nil, errors.New("no monitor configured")
+
+	case ah.authorizer == nil:
+		return nil, errors.New("no authorizer configured")
+	}
+
+	for _, method := range []string{http.MethodPut, http.MethodPost} {
+		ah.mux.HandleFunc(method+" /{name}/status", ah.updateStatus)
+		ah.mux.HandleFunc(method+" /{name}/override", ah.setOverride)
+	}
+
+	ah.mux.HandleFunc("POST /{name}/probe", ah.probe)
+	ah.mux.HandleFunc("POST /{name}/pause", ah.pause)
+	ah.mux.HandleFunc("POST /{name}/resume", ah.resume)
+	ah.mux.HandleFunc("DELETE /{name}/override", ah.clearOverride)
+	return ah, nil
+}
+
+// ServeHTTP authorizes the request, then dispatches it to the appropriate operation.
+func (ah *AdminHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	if err := ah.authorizer.Authorize(request); err != nil {
+		code := http.StatusForbidden
+		if c, ok := ah.authorizer.(Challenger); ok && errors.Is(err, ErrUnauthenticated) {
+			if challenge := c.Challenge(request); len(challenge) > 0 {
+				response.Header().Set("WWW-Authenticate", challenge)
+				code = http.StatusUnauthorized
+			}
+		}
+
+		http.Error(response, err.Error(), code)
+		return
+	}
+
+	ah.mux.ServeHTTP(response, request)
+}
+
+func (ah *AdminHandler) updateStatus(response http.ResponseWriter, request *http.Request) {
+	var body StatusRequest
+	n := Name(request.PathValue("name"))
+	u, err := ah.monitor.Get(n)
+	if err == nil {
+		err = decodeAdminRequest(response, request, &body)
+	}
+
+	if err == nil {
+		var updateErr error
+		if len(body.Error) > 0 {
+			updateErr = errors.New(body.Error)
+		}
+
+		u.Update(body.Status, updateErr)
+	}
+
+	ah.respond(response, n, err)
+}
+
+func (ah *AdminHandler) probe(response http.ResponseWriter, request *http.Request) {
+	n := Name(request.PathValue("name"))
+	_, err := ah.monitor.ProbeNow(request.Context(), n)
+	ah.respond(response, n, err)
+}
+
+func (ah *AdminHandler) pause(response http.ResponseWriter, request *http.Request) {
+	n := Name(request.PathValue("name"))
+	ah.respond(response, n, ah.monitor.Pause(n))
+}
+
+func (ah *AdminHandler) resume(response http.ResponseWriter, request *http.Request) {
+	n := Name(request.PathValue("name"))
+	ah.respond(response, n, ah.monitor.Resume(n))
+}
+
+func (ah *AdminHandler) setOverride(response http.ResponseWriter, request *http.Request) {
+	var body OverrideRequest
+	n := Name(request.PathValue("name"))
+	err := decodeAdminRequest(response, request, &body)
+	switch {
+	case err != nil:
+	case body.Exclude:
+		err = ah.monitor.Exclude(n, body.Reason, body.Until)
+
+	default:
+		err = ah.monitor.Override(n, body.Status, body.Reason, body.Until)
+	}
+
+	ah.respond(response, n, err)
+}
+
+func (ah *AdminHandler) clearOverride(response http.ResponseWriter, request *http.Request) {
+	n := Name(request.PathValue("name"))
+	ah.respond(response, n, ah.monitor.ClearOverride(n))
+}
+
+// respond writes either the error from an operation or the resulting snapshot
+// of the given subsystem.
+func (ah *AdminHandler) respond(response http.ResponseWriter, n Name, err error) {
+	var data []byte
+	if err == nil {
+		var s Subsystem
+		if s, err = ah.monitor.Subsystem(n); err == nil {
+			data, err = json.Marshal(s)
+		}
+	}
+
+	if err != nil {
+		http.Error(response, err.Error(), adminErrorCode(err))
+		return
+	}
+
+	response.Header().Set("Content-Type", "application/json")
+	response.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	response.WriteHeader(http.StatusOK)
+	_, _ = response.Write(data)
+}
+
+// adminRequestError indicates a malformed request body.
+type adminRequestError struct {
+	err error
+}
+
+func (are *adminRequestError) Error() string {
+	return fmt.Sprintf("invalid request body: %s", are.err)
+}
+
+func (are *adminRequestError) Unwrap() error {
+	return are.err
+}
+
+// decodeAdminRequest decodes a JSON request body into v.
+func decodeAdminRequest(response http.ResponseWriter, request *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(response, request.Body, maxAdminRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return &adminRequestError{err: err}
+	}
+
+	return nil
+}
+
+// adminErrorCode determines the HTTP response code for an error from an admin operation.
+func adminErrorCode(err error) int {
+	var are *adminRequestError
+	switch {
+	case errors.As(err, &are):
+		return http.StatusBadRequest
+
+	case errors.Is(err, ErrNoSuchSubsystem):
+		return http.StatusNotFound
+
+	case errors.Is(err, ErrSubsystemPaused), errors.Is(err, ErrSubsystemNotPaused), errors.Is(err, ErrNoProbe):
+		return http.StatusConflict
+
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// challengeAuthorizer is an Authorizer that also supplies a challenge.
+type challengeAuthorizer struct {
+	AuthorizerFunc
+	challenge string
+}
+
+func (ca challengeAuthorizer) Challenge(*http.Request) string {
+	return ca.challenge
+}
+
+type AdminHandlerTestSuite struct {
+	suite.Suite
+
+	monitor *Monitor
+	handler *AdminHandler
+}
+
+func (suite *AdminHandlerTestSuite) SetupTest() {
+	var err error
+	suite.monitor, err = NewMonitor(
+		WithSubsystems(
+			Definition{Name: "db"},
+			Definition{
+				Name: "cache",
+				Probe: func(context.Context) (Status, error) {
+					return StatusWarn, nil
+				},
+			},
+		),
+	)
+
+	suite.Require().NoError(err)
+	suite.handler, err = NewAdminHandler(
+		suite.monitor,
+		WithAuthorizer(challengeAuthorizer{
+			AuthorizerFunc: suite.authorize,
+			challenge:      `Bearer realm="admin"`,
+		}),
+	)
+
+	suite.Require().NoError(err)
+}
+
+func (suite *AdminHandlerTestSuite) authorize(r *http.Request) error {
+	switch r.Header.Get("Authorization") {
+	case "Bearer admin":
+		return nil
+
+	case "":
+		return ErrUnauthenticated
+
+	default:
+		return errors.New("not an admin")
+	}
+}
+
+// serve sends an authorized request to the handler under test.
+func (suite *AdminHandlerTestSuite) serve(method, target, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Authorization", "Bearer admin")
+	response := httptest.NewRecorder()
+	suite.handler.ServeHTTP(response, request)
+	return response
+}
+
+// assertSubsystem verifies that the response is the current JSON snapshot of the given subsystem.
+func (suite *AdminHandlerTestSuite) assertSubsystem(response *httptest.ResponseRecorder, n Name) Subsystem {
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	suite.Equal("application/json", response.Header().Get("Content-Type"))
+
+	s, err := suite.monitor.Subsystem(n)
+	suite.Require().NoError(err)
+
+	expected, err := json.Marshal(s)
+	suite.Require().NoError(err)
+	suite.JSONEq(string(expected), response.Body.String())
+	return s
+}
+
+func (suite *AdminHandlerTestSuite) TestNew() {
+	_, err := NewAdminHandler(suite.monitor)
+	suite.Error(err)
+
+	_, err = NewAdminHandler(nil, WithAuthorizer(AuthorizerFunc(func(*http.Request) error { return nil })))
+	suite.Error(err)
+
+	expectedErr := errors.New("expected")
+	_, err = NewAdminHandler(suite.monitor, adminHandlerOptionFunc(func(*AdminHandler) error { return expectedErr }))
+	suite.ErrorIs(err, expectedErr)
+}
+
+func (suite *AdminHandlerTestSuite) TestAuthorization() {
+	request := httptest.NewRequest(http.MethodPost, "/db/pause", nil)
+	response := httptest.NewRecorder()
+	suite.handler.ServeHTTP(response, request)
+	suite.Equal(http.StatusUnauthorized, response.Code)
+	suite.Equal(`Bearer realm="admin"`, response.Header().Get("WWW-Authenticate"))
+
+	request.Header.Set("Authorization", "Bearer guest")
+	response = httptest.NewRecorder()
+	suite.handler.ServeHTTP(response, request)
+	suite.Equal(http.StatusForbidden, response.Code)
+	suite.Empty(response.Header().Get("WWW-Authenticate"))
+
+	s, err := suite.monitor.Subsystem("db")
+	suite.NoError(err)
+	suite.False(s.Paused)
+}
+
+func (suite *AdminHandlerTestSuite) TestAuthorizationWithoutChallenge() {
+	for _, a := range []Authorizer{
+		AuthorizerFunc(suite.authorize),
+		challengeAuthorizer{AuthorizerFunc: suite.authorize},
+	} {
+		h, err := NewAdminHandler(suite.monitor, WithAuthorizer(a))
+		suite.Require().NoError(err)
+
+		// without a challenge, a 401 is not possible
+		response := httptest.NewRecorder()
+		h.ServeHTTP(response, httptest.NewRequest(http.MethodPost, "/db/pause", nil))
+		suite.Equal(http.StatusForbidden, response.Code)
+		suite.Empty(response.Header().Get("WWW-Authenticate"))
+	}
+}
+
+func (suite *AdminHandlerTestSuite) TestStatus() {
+	for _, method := range []string{http.MethodPut, http.MethodPost} {
+		suite.Run(method, func() {
+			s := suite.assertSubsystem(suite.serve(method, "/db/status", `{"status": "bad", "error": "disk full"}`), "db")
+			suite.Equal(StatusBad, s.Status)
+			suite.EqualError(s.LastError, "disk full")
+			suite.Equal(StatusBad, suite.monitor.State().Status)
+
+			s = suite.assertSubsystem(suite.serve(method, "/db/status", `{"status": "good"}`), "db")
+			suite.Equal(StatusGood, s.Status)
+			suite.NoError(s.LastError)
+		})
+	}
+
+	suite.Run("Invalid", func() {
+		suite.Equal(http.StatusBadRequest, suite.serve(http.MethodPut, "/db/status", `{"status": "nosuch"}`).Code)
+		suite.Equal(http.StatusBadRequest, suite.serve(http.MethodPut, "/db/status", `{"unknown": true}`).Code)
+		suite.Equal(http.StatusNotFound, suite.serve(http.MethodPut, "/nosuch/status", `{"status": "bad"}`).Code)
+		suite.Equal(http.StatusMethodNotAllowed, suite.serve(http.MethodGet, "/db/status", "").Code)
+	})
+}
+
+func (suite *AdminHandlerTestSuite) TestProbe() {
+	s := suite.assertSubsystem(suite.serve(http.MethodPost, "/cache/probe", ""), "cache")
+	suite.Equal(StatusWarn, s.Status)
+
+	suite.Equal(http.StatusConflict, suite.serve(http.MethodPost, "/db/probe", "").Code)
+	suite.Equal(http.StatusNotFound, suite.serve(http.MethodPost, "/nosuch/probe", "").Code)
+}
+
+func (suite *AdminHandlerTestSuite) TestPauseResume() {
+	s := suite.assertSubsystem(suite.serve(http.MethodPost, "/db/pause", ""), "db")
+	suite.True(s.Paused)
+	suite.Equal(http.StatusConflict, suite.serve(http.MethodPost, "/db/pause", "").Code)
+
+	s = suite.assertSubsystem(suite.serve(http.MethodPost, "/db/resume", ""), "db")
+	suite.False(s.Paused)
+	suite.Equal(http.StatusConflict, suite.serve(http.MethodPost, "/db/resume", "").Code)
+	suite.Equal(http.StatusNotFound, suite.serve(http.MethodPost, "/nosuch/pause", "").Code)
+}
+
+func (suite *AdminHandlerTestSuite) TestOverride() {
+	s := suite.assertSubsystem(
+		suite.serve(http.MethodPut, "/db/override", `{"status": "warn", "reason": "upgrade", "until": "2100-01-01T00:00:00Z"}`),
+		"db",
+	)
+
+	suite.Require().NotNil(s.Override)
+	suite.Equal(StatusWarn, s.Status)
+	suite.Equal("upgrade", s.Override.Reason)
+	suite.Equal(2100, s.Override.Until.Year())
+
+	s = suite.assertSubsystem(suite.serve(http.MethodPost, "/db/override", `{"exclude": true}`), "db")
+	suite.Require().NotNil(s.Override)
+	suite.True(s.Override.Exclude)
+
+	s = suite.assertSubsystem(suite.serve(http.MethodDelete, "/db/override", ""), "db")
+	suite.Nil(s.Override)
+
+	suite.Equal(http.StatusBadRequest, suite.serve(http.MethodPut, "/db/override", `not json`).Code)
+	suite.Equal(http.StatusNotFound, suite.serve(http.MethodDelete, "/nosuch/override", "").Code)
+}
+
+func TestAdminHandler(t *testing.T) {
+	suite.Run(t, new(AdminHandlerTestSuite))
+}
@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import "reflect"
+
+// Aggregator is a strategy for computing the overall status of a Monitor
+// from the states of its subsystems.
+//
+// A Monitor never passes paused subsystems to its Aggregator. The Subsystems
+// passed to Aggregate may be empty.
+type Aggregator interface {
+	// Aggregate computes an overall status from a set of subsystem snapshots.
+	Aggregate(Subsystems) Status
+}
+
+// AggregatorFunc is a closure type that implements Aggregator.
+type AggregatorFunc func(Subsystems) Status
+
+// Aggregate invokes this closure.
+func (af AggregatorFunc) Aggregate(ss Subsystems) Status {
+	return af(ss)
+}
+
+// CriticalAggregator returns the default Aggregator used by a Monitor.
+//
+// The returned Aggregator honors each subsystem's NonCritical flag. If any critical
+// subsystem is not StatusGood, the overall status is the worst critical status. Otherwise,
+// if any noncritical subsystem is not StatusGood, the overall status is StatusWarn.
+func CriticalAggregator() Aggregator {
+	return AggregatorFunc(func(ss Subsystems) Status {
+		var criticalStatus, nonCriticalStatus Status
+		for s := range ss.All() {
+			switch {
+			case s.NonCritical && s.Status > nonCriticalStatus:
+				nonCriticalStatus = s.Status
+
+			case !s.NonCritical && s.Status > criticalStatus:
+				criticalStatus = s.Status
+			}
+		}
+
+		switch {
+		case criticalStatus != StatusGood:
+			return criticalStatus
+
+		case nonCriticalStatus != StatusGood:
+			return StatusWarn
+
+		default:
+			return StatusGood
+		}
+	})
+}
+
+// WorstOfAggregator returns an Aggregator that produces the worst status of any
+// critical subsystem. Noncritical subsystems are ignored entirely.
+func WorstOfAggregator() Aggregator {
+	return AggregatorFunc(func(ss Subsystems) (worst Status) {
+		for s := range ss.All() {
+			if !s.NonCritical && s.Status > worst {
+				worst = s.Status
+			}
+		}
+
+		return
+	})
+}
+
+// QuorumAggregator returns an Aggregator suitable for sets of redundant subsystems,
+// such as replicas. The overall status is StatusBad only if the fraction of subsystems
+// that are StatusBad exceeds the given threshold. Otherwise, the overall status is
+// StatusWarn if any subsystem is not StatusGood.
+//
+// For example, a threshold of 0.5 means that more than half of the subsystems
+// must be StatusBad for the overall status to be StatusBad. The NonCritical flag
+// is ignored by this Aggregator.
+func QuorumAggregator(threshold float64) Aggregator {
+	return AggregatorFunc(func(ss Subsystems) Status {
+		var bad, unhealthy int
+		for s := range ss.All() {
+			if s.Status == StatusBad {
+				bad++
+			}
+
+			if s.Status != StatusGood {
+				unhealthy++
+			}
+		}
+
+		switch {
+		case ss.Len() > 0 && float64(bad)/float64(ss.Len()) > threshold:
+			return StatusBad
+
+		case unhealthy > 0:
+			return StatusWarn
+
+		default:
+			return StatusGood
+		}
+	})
+}
+
+// DefaultWeight is the weight used by WeightedAggregator for subsystems that
+// have no weight in their Metadata.
+const DefaultWeight float64 = 1.0
+
+// WeightedAggregator returns an Aggregator that computes a weighted score for the
+// subsystems. Each subsystem's weight is taken from its Metadata using the given key.
+// Subsystems without a numeric weight have DefaultWeight.
+//
+// The score is the weighted average of each subsystem's status, where StatusGood
+// counts as 0.0, StatusWarn as 0.5, and StatusBad as 1.0. The overall status is
+// StatusBad if the score is at least badAt, StatusWarn if the score is positive and
+// at least warnAt, and StatusGood otherwise. The NonCritical flag is ignored by
+// this Aggregator.
+func WeightedAggregator(key string, warnAt, badAt float64) Aggregator {
+	return AggregatorFunc(func(ss Subsystems) Status {
+		var total, score float64
+		for s := range ss.All() {
+			weight := DefaultWeight
+			if v, ok := s.Metadata.Get(key); ok {
+				if w, ok := toWeight(v); ok {
+					weight = w
+				}
+			}
+
+			total += weight
+			score += weight * float64(s.Status) / float64(StatusBad)
+		}
+
+		if total > 0 {
+			score /= total
+		}
+
+		switch {
+		case score > 0 && score >= badAt:
+			return StatusBad
+
+		case score > 0 && score >= warnAt:
+			return StatusWarn
+
+		default:
+			return StatusGood
+		}
+	})
+}
+
+// toWeight converts an arbitrary metadata value into a nonnegative weight.
+func toWeight(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	var w float64
+	switch {
+	case rv.CanInt():
+		w = float64(rv.Int())
+
+	case rv.CanUint():
+		w = float64(rv.Uint())
+
+	case rv.CanFloat():
+		w = rv.Float()
+
+	default:
+		return 0, false
+	}
+
+	return w, w >= 0
+}
+
+// WithAggregator sets the strategy used to compute the overall status of a Monitor.
+// If unset or nil, CriticalAggregator is used.
+func WithAggregator(a Aggregator) MonitorOption {
+	return monitorOptionFunc(func(m *Monitor) error {
+		if a == nil {
+			a = CriticalAggregator()
+		}
+
+		m.aggregator = a
+		return nil
+	})
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type AggregatorTestSuite struct {
+	suite.Suite
+}
+
+// aggregatorTestCase is a single set of subsystems and the expected
+// aggregate status.
+type aggregatorTestCase struct {
+	name       string
+	subsystems []Subsystem
+	expected   Status
+}
+
+func (suite *AggregatorTestSuite) runTestCases(a Aggregator, testCases []aggregatorTestCase) {
+	suite.Require().NotNil(a)
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			suite.Equal(
+				testCase.expected,
+				a.Aggregate(AsSubsystems(testCase.subsystems...)),
+			)
+		})
+	}
+}
+
+func (suite *AggregatorTestSuite) TestAggregatorFunc() {
+	var called bool
+	af := AggregatorFunc(func(ss Subsystems) Status {
+		called = true
+		suite.Equal(1, ss.Len())
+		return StatusWarn
+	})
+
+	suite.Equal(StatusWarn, af.Aggregate(AsSubsystems(Subsystem{Name: "test"})))
+	suite.True(called)
+}
+
+func (suite *AggregatorTestSuite) TestCriticalAggregator() {
+	suite.runTestCases(CriticalAggregator(), []aggregatorTestCase{
+		{name: "Empty", expected: StatusGood},
+		{
+			name:       "CriticalWarn",
+			subsystems: []Subsystem{{Status: StatusGood}, {Status: StatusWarn}},
+			expected:   StatusWarn,
+		},
+		{
+			name:       "CriticalBad",
+			subsystems: []Subsystem{{Status: StatusBad}, {Status: StatusWarn}},
+			expected:   StatusBad,
+		},
+		{
+			name:       "NonCriticalBad",
+			subsystems: []Subsystem{{Status: StatusGood}, {Status: StatusBad, NonCritical: true}},
+			expected:   StatusWarn,
+		},
+	})
+}
+
+func (suite *AggregatorTestSuite) TestWorstOfAggregator() {
+	suite.runTestCases(WorstOfAggregator(), []aggregatorTestCase{
+		{name: "Empty", expected: StatusGood},
+		{
+			name:       "CriticalBad",
+			subsystems: []Subsystem{{Status: StatusWarn}, {Status: StatusBad}},
+			expected:   StatusBad,
+		},
+		{
+			name:       "NonCriticalIgnored",
+			subsystems: []Subsystem{{Status: StatusGood}, {Status: StatusBad, NonCritical: true}},
+			expected:   StatusGood,
+		},
+	})
+}
+
+func (suite *AggregatorTestSuite) TestQuorumAggregator() {
+	suite.runTestCases(QuorumAggregator(0.5), []aggregatorTestCase{
+		{name: "Empty", expected: StatusGood},
+		{
+			name:       "AllGood",
+			subsystems: []Subsystem{{Status: StatusGood}, {Status: StatusGood}, {Status: StatusGood}},
+			expected:   StatusGood,
+		},
+		{
+			name:       "MinorityBad",
+			subsystems: []Subsystem{{Status: StatusBad}, {Status: StatusGood}, {Status: StatusGood}},
+			expected:   StatusWarn,
+		},
+		{
+			name:       "HalfBad",
+			subsystems: []Subsystem{{Status: StatusBad}, {Status: StatusGood}},
+			expected:   StatusWarn,
+		},
+		{
+			name:       "MajorityBad",
+			subsystems: []Subsystem{{Status: StatusBad}, {Status: StatusBad}, {Status: StatusGood}},
+			expected:   StatusBad,
+		},
+	})
+}
+
+func (suite *AggregatorTestSuite) TestWeightedAggregator() {
+	suite.runTestCases(WeightedAggregator("weight", 0.25, 0.5), []aggregatorTestCase{
+		{name: "Empty", expected: StatusGood},
+		{
+			name: "HeavyBad",
+			subsystems: []Subsystem{
+				{Status: StatusBad, Metadata: Values("weight", 3)},
+				{Status: StatusGood},
+			},
+			expected: StatusBad,
+		},
+		{
+			name: "LightBad",
+			subsystems: []Subsystem{
+				{Status: StatusBad, Metadata: Values("weight", uint8(1))},
+				{Status: StatusGood, Metadata: Values("weight", 2.0)},
+			},
+			expected: StatusWarn,
+		},
+		{
+			name: "NegligibleWarn",
+			subsystems: []Subsystem{
+				{Status: StatusWarn, Metadata: Values("weight", 0.1)},
+				{Status: StatusGood, Metadata: Values("weight", 10)},
+			},
+			expected: StatusGood,
+		},
+		{
+			name: "InvalidWeights",
+			subsystems: []Subsystem{
+				{Status: StatusBad, Metadata: Values("weight", "heavy")},
+				{Status: StatusGood, Metadata: Values("weight", -5)},
+			},
+			expected: StatusBad,
+		},
+	})
+
+	suite.Run("AlwaysGoodWhenHealthy", func() {
+		a := WeightedAggregator("weight", 0, 0)
+		suite.Equal(StatusGood, a.Aggregate(AsSubsystems(Subsystem{Status: StatusGood})))
+		suite.Equal(StatusBad, a.Aggregate(AsSubsystems(Subsystem{Status: StatusWarn})))
+	})
+}
+
+func TestAggregator(t *testing.T) {
+	suite.Run(t, new(AggregatorTestSuite))
+}
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"encoding/hex"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// entityTag computes a strong entity tag for an encoded response body. Since the body
+// is derived entirely from the state, equal tags indicate identical representations.
+func entityTag(body []byte) string {
+	h := fnv.New64a()
+	h.Write(body)
+	return `"` + hex.EncodeToString(h.Sum(nil)) + `"`
+}
+
+// etagMatches tests if an If-None-Match header matches the given entity tag. As
+// If-None-Match uses the weak comparison, any W/ prefix is ignored.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, v := range strings.Split(ifNoneMatch, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || strings.TrimPrefix(v, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// notModified tests if a request's preconditions indicate that the client already
+// has the current representation. If-Modified-Since is only consulted when the
+// request has no If-None-Match header.
+func notModified(request *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := request.Header.Get("If-None-Match"); len(ifNoneMatch) > 0 {
+		return etagMatches(ifNoneMatch, etag)
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(request.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	// Last-Modified only has a resolution of seconds
+	return !lastModified.Truncate(time.Second).After(ifModifiedSince)
+}
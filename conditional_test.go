@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ConditionalTestSuite struct {
+	suite.Suite
+}
+
+func (suite *ConditionalTestSuite) TestEntityTag() {
+	etag := entityTag([]byte("test"))
+	suite.Regexp(`^"[0-9a-f]{16}"$`, etag)
+	suite.Equal(etag, entityTag([]byte("test")))
+	suite.NotEqual(etag, entityTag([]byte("other")))
+}
+
+func (suite *ConditionalTestSuite) TestEtagMatches() {
+	suite.True(etagMatches(`"a"`, `"a"`))
+	suite.True(etagMatches(`W/"a"`, `"a"`))
+	suite.True(etagMatches(`"b", "a"`, `"a"`))
+	suite.True(etagMatches(`*`, `"a"`))
+	suite.False(etagMatches(`"b"`, `"a"`))
+	suite.False(etagMatches(`a`, `"a"`))
+}
+
+func (suite *ConditionalTestSuite) TestNotModified() {
+	lastModified := time.Date(2025, time.March, 1, 12, 0, 0, 500, time.UTC)
+	newRequest := func(header, value string) *http.Request {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if len(header) > 0 {
+			request.Header.Set(header, value)
+		}
+
+		return request
+	}
+
+	suite.False(notModified(newRequest("", ""), `"a"`, lastModified))
+	suite.True(notModified(newRequest("If-None-Match", `"a"`), `"a"`, lastModified))
+	suite.False(notModified(newRequest("If-None-Match", `"b"`), `"a"`, lastModified))
+	suite.True(notModified(newRequest("If-Modified-Since", lastModified.Format(http.TimeFormat)), `"a"`, lastModified))
+	suite.False(notModified(newRequest("If-Modified-Since", lastModified.Add(-time.Second).Format(http.TimeFormat)), `"a"`, lastModified))
+	suite.False(notModified(newRequest("If-Modified-Since", "not a time"), `"a"`, lastModified))
+	suite.False(notModified(newRequest("If-Modified-Since", lastModified.Format(http.TimeFormat)), `"a"`, time.Time{}))
+
+	// If-None-Match takes precedence over If-Modified-Since
+	request := newRequest("If-None-Match", `"b"`)
+	request.Header.Set("If-Modified-Since", lastModified.Format(http.TimeFormat))
+	suite.False(notModified(request, `"a"`, lastModified))
+}
+
+func TestConditional(t *testing.T) {
+	suite.Run(t, new(ConditionalTestSuite))
+}
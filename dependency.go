@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DependencyError describes a subsystem that is StatusBad because one of
+// the subsystems it depends on is StatusBad.
+type DependencyError struct {
+	// Subsystem is the name of the subsystem affected by the failed dependency.
+	Subsystem Name `json:"subsystem" yaml:"subsystem"`
+
+	// Dependency is the name of the direct dependency that is StatusBad.
+	Dependency Name `json:"dependency" yaml:"dependency"`
+
+	// Root is the name of the subsystem that is the root cause of the failure.
+	// This is the first subsystem in the chain of dependencies that is StatusBad
+	// on its own, rather than because of another dependency.
+	Root Name `json:"root" yaml:"root"`
+}
+
+// Error describes the chain of dependencies that caused the failure.
+func (de *DependencyError) Error() string {
+	if de.Dependency == de.Root {
+		return fmt.Sprintf("subsystem [%s] depends on [%s], which is bad", de.Subsystem, de.Dependency)
+	}
+
+	return fmt.Sprintf("subsystem [%s] depends on [%s], which is bad due to [%s]", de.Subsystem, de.Dependency, de.Root)
+}
+
+// Status always returns StatusBad.
+func (de *DependencyError) Status() Status {
+	return StatusBad
+}
+
+// sortDependencies orders the given trackers so that every subsystem appears after
+// all of the subsystems it depends on. An error is returned if any dependency is not
+// defined or if the dependencies contain a cycle.
+func sortDependencies(byName map[Name]*subsystemTracker, trackers []*subsystemTracker) ([]*subsystemTracker, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	var (
+		order = make([]*subsystemTracker, 0, len(trackers))
+		state = make(map[*subsystemTracker]int, len(trackers))
+		path  []Name
+		visit func(*subsystemTracker) error
+	)
+
+	visit = func(sst *subsystemTracker) error {
+		switch state[sst] {
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", formatCycle(path, sst.definition.Name))
+
+		case visited:
+			return nil
+		}
+
+		state[sst] = visiting
+		path = append(path, sst.definition.Name)
+		for _, n := range sst.definition.DependsOn {
+			dep := byName[n]
+			if dep == nil {
+				return fmt.Errorf("subsystem [%s] depends on [%s], which does not exist", sst.definition.Name, n)
+			}
+
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[sst] = visited
+		order = append(order, sst)
+		return nil
+	}
+
+	for _, sst := range trackers {
+		if err := visit(sst); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// formatCycle produces a human-readable cycle, e.g. a -> b -> a, given the current
+// DFS path and the name that was revisited.
+func formatCycle(path []Name, revisited Name) string {
+	var o strings.Builder
+	start := 0
+	for i, n := range path {
+		if n == revisited {
+			start = i
+			break
+		}
+	}
+
+	for _, n := range path[start:] {
+		o.WriteString(string(n))
+		o.WriteString(" -> ")
+	}
+
+	o.WriteString(string(revisited))
+	return o.String()
+}
+
+// unsafeApplyDependencies makes a subsystem StatusBad if any of its dependencies are
+// StatusBad. Every dependency must already have its effective status computed.
+//
+// This method must be executed under the monitor lock.
+func (sst *subsystemTracker) unsafeApplyDependencies(byName map[Name]*subsystemTracker) {
+	sst.current.DependencyError = nil
+	for _, n := range sst.definition.DependsOn {
+		// paused or excluded dependencies do not affect their dependents
+		dep := byName[n].current
+		if !dep.active() || dep.Status != StatusBad {
+			continue
+		}
+
+		root := n
+		if dep.DependencyError != nil {
+			root = dep.DependencyError.Root
+		}
+
+		sst.current.Status = StatusBad
+		sst.current.DependencyError = &DependencyError{
+			Subsystem:  sst.definition.Name,
+			Dependency: n,
+			Root:       root,
+		}
+
+		return
+	}
+}
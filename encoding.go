@@ -0,0 +1,256 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Encoder is a strategy for writing a Handler's response body in a particular
+// media type.
+//
+// The value passed to Encode is one of: MonitorState, DetailedState, Selection,
+// or Subsystem.
+type Encoder interface {
+	// Encode writes the given value.
+	Encode(w io.Writer, v any) error
+}
+
+// EncoderFunc is a closure type that implements Encoder.
+type EncoderFunc func(io.Writer, any) error
+
+// Encode invokes this closure.
+func (ef EncoderFunc) Encode(w io.Writer, v any) error {
+	return ef(w, v)
+}
+
+// JSONEncoder returns an Encoder that writes JSON. This is the default Encoder
+// used by a Handler.
+func JSONEncoder() Encoder {
+	return EncoderFunc(func(w io.Writer, v any) error {
+		data, err := json.Marshal(v)
+		if err == nil {
+			_, err = w.Write(data)
+		}
+
+		return err
+	})
+}
+
+// YAMLEncoder returns an Encoder that writes YAML.
+func YAMLEncoder() Encoder {
+	return EncoderFunc(func(w io.Writer, v any) error {
+		e := yaml.NewEncoder(w)
+		if err := e.Encode(v); err != nil {
+			return err
+		}
+
+		return e.Close()
+	})
+}
+
+// TextEncoder returns an Encoder that writes a terse, line-oriented format suitable
+// for curl. The overall status, if any, is written first as "status: <status>",
+// followed by one "<name>: <status>" line for each subsystem.
+func TextEncoder() Encoder {
+	return EncoderFunc(func(w io.Writer, v any) (err error) {
+		status, hasStatus, subsystems := summarize(v)
+		var o strings.Builder
+		if hasStatus {
+			fmt.Fprintf(&o, "status: %s\n", status)
+		}
+
+		for s := range subsystems.All() {
+			fmt.Fprintf(&o, "%s: %s\n", s.Name, s.Status)
+		}
+
+		_, err = io.WriteString(w, o.String())
+		return
+	})
+}
+
+// htmlTemplate is the self-contained status page written by HTMLEncoder.
+var htmlTemplate = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Health: {{.Status}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: left; }
+.good { background: #d4edda; }
+.warn { background: #fff3cd; }
+.bad { background: #f8d7da; }
+</style>
+</head>
+<body>
+{{if .HasStatus}}<h1 class="{{.Status}}">Status: {{.Status}}</h1>
+{{end}}<table>
+<tr><th>Subsystem</th><th>Status</th><th>Observed</th><th>Last Update</th><th>Error</th></tr>
+{{range .Subsystems}}<tr class="{{.Status}}"><td>{{.Name}}</td><td>{{.Status}}</td><td>{{.ObservedStatus}}</td><td>{{.LastUpdate.Format "2006-01-02T15:04:05Z07:00"}}</td><td>{{with .LastError}}{{.Error}}{{end}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// HTMLEncoder returns an Encoder that writes a simple, self-contained HTML status page.
+func HTMLEncoder() Encoder {
+	return EncoderFunc(func(w io.Writer, v any) error {
+		status, hasStatus, subsystems := summarize(v)
+
+		// templates cannot range over an iter.Seq prior to go 1.24
+		return htmlTemplate.Execute(w, struct {
+			Status     Status
+			HasStatus  bool
+			Subsystems []Subsystem
+		}{
+			Status:     status,
+			HasStatus:  hasStatus,
+			Subsystems: slices.Collect(subsystems.All()),
+		})
+	})
+}
+
+// summarize extracts the overall status, if any, and the subsystems from a value
+// rendered by a Handler.
+func summarize(v any) (status Status, hasStatus bool, subsystems Subsystems) {
+	switch vt := v.(type) {
+	case MonitorState:
+		return vt.Status, true, vt.Subsystems
+
+	case DetailedState:
+		return vt.Status, true, vt.Subsystems
+
+	case Selection:
+		return vt.Status, true, vt.Subsystems
+
+	case Subsystem:
+		return vt.Status, false, AsSubsystems(vt)
+
+	default:
+		return
+	}
+}
+
+// mediaEncoder associates an Encoder with the media type it produces.
+type mediaEncoder struct {
+	// contentType is the full Content-Type written with responses.
+	contentType string
+
+	// mediaType is the media type without parameters, used for negotiation.
+	mediaType string
+
+	encoder Encoder
+}
+
+// defaultEncoders returns the encoders a Handler supports by default. The first
+// encoder is used when a request expresses no preference.
+func defaultEncoders() []mediaEncoder {
+	return []mediaEncoder{
+		{contentType: "application/json", mediaType: "application/json", encoder: JSONEncoder()},
+		{contentType: "application/yaml", mediaType: "application/yaml", encoder: YAMLEncoder()},
+		{contentType: "text/plain; charset=utf-8", mediaType: "text/plain", encoder: TextEncoder()},
+		{contentType: "text/html; charset=utf-8", mediaType: "text/html", encoder: HTMLEncoder()},
+	}
+}
+
+// WithEncoder registers an Encoder for a media type, replacing any Encoder already
+// registered for that media type. The contentType is written as the response's
+// Content-Type and may include parameters, e.g. "text/csv; charset=utf-8". If e
+// is nil, any Encoder for the media type is removed.
+//
+// By default, a Handler supports application/json, application/yaml, text/plain,
+// and text/html. Requests are matched to an Encoder using their Accept header. When a
+// request has no Accept header or no Encoder is acceptable, the first registered Encoder
+// is used, which is JSON unless it has been removed.
+func WithEncoder(contentType string, e Encoder) HandlerOption {
+	return handlerOptionFunc(func(h *Handler) error {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return fmt.Errorf("invalid content type [%s]: %w", contentType, err)
+		}
+
+		h.encoders = slices.DeleteFunc(h.encoders, func(me mediaEncoder) bool {
+			return me.mediaType == mediaType
+		})
+
+		if e != nil {
+			h.encoders = append(h.encoders, mediaEncoder{
+				contentType: contentType,
+				mediaType:   mediaType,
+				encoder:     e,
+			})
+		}
+
+		return nil
+	})
+}
+
+// acceptedRange is a single media range from an Accept header.
+type acceptedRange struct {
+	mediaType string
+	quality   float64
+}
+
+// parseAccept parses an Accept header into media ranges, from most to least preferred.
+// Ranges that are malformed or have a quality of zero are omitted.
+func parseAccept(accept string) (ranges []acceptedRange) {
+	for _, v := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		if quality > 0 {
+			ranges = append(ranges, acceptedRange{mediaType: mediaType, quality: quality})
+		}
+	}
+
+	// the sort is stable, so equally preferred ranges keep their original order
+	slices.SortStableFunc(ranges, func(a, b acceptedRange) int {
+		return cmp.Compare(b.quality, a.quality)
+	})
+
+	return
+}
+
+// negotiate selects the registered encoder that best matches an Accept header.
+// If the header is empty or no encoder matches, the first encoder is used.
+func negotiate(encoders []mediaEncoder, accept string) mediaEncoder {
+	for _, r := range parseAccept(accept) {
+		prefix, wildcard := strings.CutSuffix(r.mediaType, "/*")
+		for _, me := range encoders {
+			switch {
+			case r.mediaType == "*/*":
+				return me
+
+			case wildcard && strings.HasPrefix(me.mediaType, prefix+"/"):
+				return me
+
+			case me.mediaType == r.mediaType:
+				return me
+			}
+		}
+	}
+
+	return encoders[0]
+}
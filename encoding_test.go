@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.yaml.in/yaml/v3"
+)
+
+type EncodingTestSuite struct {
+	suite.Suite
+}
+
+func (suite *EncodingTestSuite) state() MonitorState {
+	return MonitorState{
+		Status:     StatusWarn,
+		LastUpdate: time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC),
+		Subsystems: AsSubsystems(
+			Subsystem{Name: "db", Status: StatusGood},
+			Subsystem{Name: "cache", Status: StatusWarn, LastError: errors.New("<slow>")},
+		),
+		Kinds: AsKindStatuses(map[Kind]Status{KindLiveness: StatusGood}),
+	}
+}
+
+func (suite *EncodingTestSuite) TestParseAccept() {
+	suite.Empty(parseAccept(""))
+	suite.Equal(
+		[]acceptedRange{
+			{mediaType: "text/html", quality: 1},
+			{mediaType: "application/yaml", quality: 1},
+			{mediaType: "text/plain", quality: 0.5},
+			{mediaType: "*/*", quality: 0.1},
+		},
+		parseAccept("text/plain;q=0.5, text/html, */*;q=0.1, image/png;q=0, application/yaml, bad/;q=1, text/csv;q=x"),
+	)
+}
+
+func (suite *EncodingTestSuite) TestNegotiate() {
+	encoders := defaultEncoders()
+	for _, testCase := range []struct {
+		accept    string
+		mediaType string
+	}{
+		{accept: "", mediaType: "application/json"},
+		{accept: "*/*", mediaType: "application/json"},
+		{accept: "text/*", mediaType: "text/plain"},
+		{accept: "text/html;q=0.9, application/yaml", mediaType: "application/yaml"},
+		{accept: "image/*, text/html;q=0.1", mediaType: "text/html"},
+		{accept: "image/png", mediaType: "application/json"},
+	} {
+		suite.Run(testCase.accept, func() {
+			suite.Equal(testCase.mediaType, negotiate(encoders, testCase.accept).mediaType)
+		})
+	}
+}
+
+func (suite *EncodingTestSuite) TestTextEncoder() {
+	var o strings.Builder
+	suite.Require().NoError(TextEncoder().Encode(&o, suite.state()))
+	suite.Equal("status: warn\ndb: good\ncache: warn\n", o.String())
+
+	o.Reset()
+	suite.Require().NoError(TextEncoder().Encode(&o, Subsystem{Name: "db", Status: StatusBad}))
+	suite.Equal("db: bad\n", o.String())
+}
+
+func (suite *EncodingTestSuite) TestYAMLEncoder() {
+	var o strings.Builder
+	suite.Require().NoError(YAMLEncoder().Encode(&o, DetailedState{MonitorState: suite.state()}))
+
+	var decoded struct {
+		Status     string `yaml:"status"`
+		Subsystems []struct {
+			Name   string `yaml:"name"`
+			Status string `yaml:"status"`
+		} `yaml:"subsystems"`
+		Kinds map[string]string `yaml:"kinds"`
+	}
+
+	suite.Require().NoError(yaml.Unmarshal([]byte(o.String()), &decoded), o.String())
+	suite.Equal("warn", decoded.Status)
+	suite.Require().Len(decoded.Subsystems, 2)
+	suite.Equal("db", decoded.Subsystems[0].Name)
+	suite.Equal("good", decoded.Subsystems[0].Status)
+	suite.Equal("cache", decoded.Subsystems[1].Name)
+	suite.Equal("warn", decoded.Subsystems[1].Status)
+	suite.Equal(map[string]string{"liveness": "good"}, decoded.Kinds)
+}
+
+func (suite *EncodingTestSuite) TestHTMLEncoder() {
+	var o strings.Builder
+	suite.Require().NoError(HTMLEncoder().Encode(&o, suite.state()))
+
+	page := o.String()
+	suite.True(strings.HasPrefix(page, "<!DOCTYPE html>"))
+	suite.Contains(page, "Status: warn")
+	suite.Contains(page, "<td>db</td>")
+	suite.Contains(page, "<td>cache</td>")
+	suite.Contains(page, "&lt;slow&gt;")
+	suite.NotContains(page, "<slow>")
+}
+
+func TestEncoding(t *testing.T) {
+	suite.Run(t, new(EncodingTestSuite))
+}
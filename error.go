@@ -3,7 +3,10 @@
 
 package haelu
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // SelfStatuser is an optional interface that an error can implement
 // to indicate its health status.
@@ -87,3 +90,24 @@ func ErrorStatus(err error) Status {
 		return StatusBad
 	}
 }
+
+// StopError is returned by Monitor.Stop when one or more background tasks, such
+// as probes, did not exit before the supplied context was canceled.
+type StopError struct {
+	// Subsystems are the names of the subsystems whose background tasks were still
+	// running when Stop returned.
+	Subsystems []Name
+
+	// Err is the context error that caused Stop to give up waiting.
+	Err error
+}
+
+// Error returns a message identifying the subsystems whose probes did not exit.
+func (se *StopError) Error() string {
+	return fmt.Sprintf("tasks for subsystems %v did not stop: %s", se.Subsystems, se.Err)
+}
+
+// Unwrap returns the context error that caused Stop to give up waiting.
+func (se *StopError) Unwrap() error {
+	return se.Err
+}
@@ -4,6 +4,7 @@
 package haelu
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -134,6 +135,18 @@ func (suite *ErrorTestSuite) TestErrorStatus() {
 	}
 }
 
+func (suite *ErrorTestSuite) TestStopError() {
+	err := &StopError{
+		Subsystems: []Name{"first", "second"},
+		Err:        context.DeadlineExceeded,
+	}
+
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Contains(err.Error(), "first")
+	suite.Contains(err.Error(), "second")
+	suite.Contains(err.Error(), context.DeadlineExceeded.Error())
+}
+
 func TestError(t *testing.T) {
 	suite.Run(t, new(ErrorTestSuite))
 }
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultFlapWindow is the window used to detect flapping when a subsystem
+// defines a FlapThreshold but no FlapWindow.
+const DefaultFlapWindow = 5 * time.Minute
+
+// initializeFlapping applies defaults for this subsystem's flapping detection.
+func (sst *subsystemTracker) initializeFlapping() {
+	sst.flapping = make(chan struct{}, 1)
+	if sst.definition.FlapThreshold < 1 {
+		return
+	}
+
+	if sst.definition.FlapWindow <= 0 {
+		sst.definition.FlapWindow = DefaultFlapWindow
+	}
+
+	if sst.definition.FlapQuietPeriod <= 0 {
+		sst.definition.FlapQuietPeriod = sst.definition.FlapWindow
+	}
+
+	if sst.definition.FlappingStatus == StatusGood {
+		sst.definition.FlappingStatus = StatusWarn
+	}
+}
+
+// unsafeApplyFlapping degrades this subsystem's effective status to its FlappingStatus
+// while it is flapping.
+//
+// This method must be executed under the monitor lock.
+func (sst *subsystemTracker) unsafeApplyFlapping() {
+	if sst.current.Flapping {
+		sst.current.Status = sst.definition.FlappingStatus
+	}
+}
+
+// unsafeTrackFlapping records a transition in this subsystem's observed status, if one
+// occurred, and determines whether this subsystem is flapping. The previous observed
+// status is supplied to detect the transition.
+//
+// This method must be executed under the monitor lock.
+func (sst *subsystemTracker) unsafeTrackFlapping(previous Status) {
+	if sst.definition.FlapThreshold < 1 {
+		return
+	}
+
+	now := sst.current.LastUpdate
+	if sst.current.Flapping && now.Sub(sst.lastTransition()) >= sst.definition.FlapQuietPeriod {
+		// the quiet period elapsed without the flap task noticing, e.g. when the Monitor
+		// isn't running
+		sst.current.Flapping = false
+		sst.transitions = nil
+	}
+
+	if previous == sst.current.ObservedStatus {
+		return
+	}
+
+	// discard transitions outside the window, keeping just enough to detect flapping
+	cutoff := now.Add(-sst.definition.FlapWindow)
+	i := 0
+	for i < len(sst.transitions) && (!sst.transitions[i].After(cutoff) || len(sst.transitions)-i > sst.definition.FlapThreshold) {
+		i++
+	}
+
+	sst.transitions = append(sst.transitions[i:], now)
+	if len(sst.transitions) > sst.definition.FlapThreshold {
+		sst.current.Flapping = true
+	}
+
+	if sst.current.Flapping {
+		// restart the quiet period without blocking
+		select {
+		case sst.flapping <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// lastTransition returns the timestamp of the most recent transition in observed
+// status, or the zero time if there have been no recent transitions.
+func (sst *subsystemTracker) lastTransition() (t time.Time) {
+	if len(sst.transitions) > 0 {
+		t = sst.transitions[len(sst.transitions)-1]
+	}
+
+	return
+}
+
+// flapTask clears this subsystem's flapping state once no transitions have occurred
+// for the quiet period. This task runs until the given context is canceled.
+func (sst *subsystemTracker) flapTask(ctx context.Context) {
+	for {
+		// wait for this subsystem to start flapping
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-sst.flapping:
+		}
+
+		for settled := false; !settled; {
+			timeCh, stop := sst.newTimer(sst.definition.FlapQuietPeriod)
+			select {
+			case <-ctx.Done():
+				stop()
+				return
+
+			case <-sst.flapping:
+				// another transition restarts the quiet period
+				stop()
+
+			case <-timeCh:
+				settled = sst.settle(ctx)
+			}
+		}
+	}
+}
+
+// settle clears this subsystem's flapping state if the quiet period has elapsed since
+// the most recent transition. This method returns true if this subsystem is no longer
+// flapping, false if the quiet period must be restarted.
+func (sst *subsystemTracker) settle(ctx context.Context) bool {
+	defer sst.lock.Unlock()
+	sst.lock.Lock()
+
+	switch {
+	case sst.removed || ctx.Err() != nil || !sst.current.Flapping:
+		return true
+
+	case sst.now().Sub(sst.lastTransition()) < sst.definition.FlapQuietPeriod:
+		return false
+	}
+
+	sst.current.Flapping = false
+	sst.transitions = nil
+	sst.unsafeUpdateState(sst.now().UTC(), sst.definition.Name)
+	return true
+}
@@ -5,6 +5,5 @@ go 1.23.4
 require (
 	github.com/stretchr/testify v1.12.1
 	github.com/xmidt-org/chronon v0.1.14
+	go.yaml.in/yaml/v3 v3.0.5
 )
-
-require go.yaml.in/yaml/v3 v3.0.5 // indirect
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"time"
+)
+
+// GroupDefinition holds the information necessary to create a group of subsystems
+// within a Monitor. A subsystem joins a group by setting Definition.Group.
+//
+// A group's status is computed from the statuses of its subsystems using the group's
+// Aggregator. The group then contributes to the Monitor's overall status as if it were
+// a single subsystem, in place of its individual members.
+type GroupDefinition struct {
+	// Name is the unique identifier for this group within the Monitor. A group's name
+	// must not be the same as any subsystem's name.
+	Name Name
+
+	// NonCritical indicates how this group affects the overall Monitor status. This
+	// field has the same meaning as Definition.NonCritical, applied to the group as
+	// a whole.
+	NonCritical bool
+
+	// Aggregator is the strategy used to compute this group's status from its subsystems.
+	// If unset, CriticalAggregator is used.
+	Aggregator Aggregator
+
+	// Metadata are optional name/value pairs to associate with this group.
+	Metadata Metadata
+}
+
+// Group is a snapshot of the current state of a group of subsystems within a Monitor.
+type Group struct {
+	// Name is the unique identifier for this group.
+	Name Name `json:"name" yaml:"name"`
+
+	// Status is the aggregated status of this group's subsystems. Paused subsystems
+	// do not contribute to this status.
+	Status Status `json:"status" yaml:"status"`
+
+	// LastUpdate is the most recent LastUpdate of any of this group's subsystems.
+	LastUpdate time.Time `json:"lastUpdate,omitempty" yaml:"lastUpdate"`
+
+	// NonCritical indicates whether this group is noncritical, i.e. how it affects
+	// the overall Monitor status.
+	NonCritical bool `json:"nonCritical" yaml:"nonCritical"`
+
+	// Metadata is the optional set of name/value pairs that were supplied when the
+	// group was defined.
+	Metadata Metadata `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// Subsystems are the snapshots of each subsystem within this group.
+	Subsystems Subsystems `json:"subsystems" yaml:"subsystems"`
+}
+
+// asSubsystem produces a Subsystem snapshot that represents this entire group. This
+// is used when aggregating the overall status of a Monitor.
+func (g Group) asSubsystem() Subsystem {
+	return Subsystem{
+		Name:        g.Name,
+		Status:      g.Status,
+		LastUpdate:  g.LastUpdate,
+		NonCritical: g.NonCritical,
+		Metadata:    g.Metadata,
+	}
+}
+
+// Groups is an immutable, iterable sequence of Group snapshots.
+type Groups struct {
+	gs []Group
+}
+
+// AsGroups creates an immutable Groups sequence from a slice of individual
+// Group instances. The returned Groups will be a shallow copy of the given slice.
+//
+// If the groups slice is empty, the returned Groups will be an immutable,
+// empty sequence.
+func AsGroups(groups ...Group) (g Groups) {
+	if len(groups) > 0 {
+		g.gs = make([]Group, len(groups))
+		copy(g.gs, groups)
+	}
+
+	return
+}
+
+// Len returns the count of Group snapshots in this sequence.
+func (g Groups) Len() int {
+	return len(g.gs)
+}
+
+// Get returns the Group at the given 0-based index. If i is
+// negative or not less than Len(), this function panics.
+func (g Groups) Get(i int) Group {
+	return g.gs[i]
+}
+
+// All provides an iterator over this immutable sequence.
+func (g Groups) All() iter.Seq[Group] {
+	return func(f func(Group) bool) {
+		for _, g := range g.gs {
+			if !f(g) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON marshals this sequence as a slice of Groups.
+func (g Groups) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.gs)
+}
+
+// MarshalYAML marshals this sequence as a slice of Groups.
+func (g Groups) MarshalYAML() (any, error) {
+	return g.gs, nil
+}
+
+// groupTracker holds the configuration for a single group within a Monitor.
+type groupTracker struct {
+	definition GroupDefinition
+
+	// subsystems and active are scratch space used while computing state
+	subsystems []Subsystem
+	active     []Subsystem
+}
+
+// unsafeAggregateGroups computes the status of each group from the subsystems collected
+// into its scratch space, then clears that scratch space. Each group with active subsystems
+// is appended to active, as it affects the overall status.
+//
+// This method must be executed under the monitor lock or in a situation where no
+// concurrent invocation is possible.
+func (m *Monitor) unsafeAggregateGroups(active []Subsystem) ([]Group, []Subsystem) {
+	var groups []Group
+	if len(m.groups) > 0 {
+		groups = make([]Group, len(m.groups))
+	}
+
+	for i, gt := range m.groups {
+		groups[i] = Group{
+			Name:        gt.definition.Name,
+			Status:      gt.definition.Aggregator.Aggregate(Subsystems{ss: gt.active}),
+			NonCritical: gt.definition.NonCritical,
+			Metadata:    gt.definition.Metadata,
+			Subsystems:  AsSubsystems(gt.subsystems...),
+		}
+
+		for _, s := range gt.subsystems {
+			if s.LastUpdate.After(groups[i].LastUpdate) {
+				groups[i].LastUpdate = s.LastUpdate
+			}
+		}
+
+		// a group with no active subsystems does not affect the overall status
+		if len(gt.active) > 0 {
+			active = append(active, groups[i].asSubsystem())
+		}
+
+		// clear the scratch space for the next update
+		clear(gt.subsystems)
+		clear(gt.active)
+		gt.subsystems, gt.active = gt.subsystems[:0], gt.active[:0]
+	}
+
+	return groups, active
+}
+
+// WithGroups defines several subsystem groups for the monitor. Subsystems
+// join a group via Definition.Group.
+func WithGroups(defs ...GroupDefinition) MonitorOption {
+	return monitorOptionFunc(func(m *Monitor) error {
+		for _, d := range defs {
+			if m.groupsByName[d.Name] != nil {
+				return fmt.Errorf("a group with the name [%s] already exists", d.Name)
+			}
+
+			if d.Aggregator == nil {
+				d.Aggregator = CriticalAggregator()
+			}
+
+			gt := &groupTracker{
+				definition: d,
+			}
+
+			m.groupsByName[d.Name] = gt
+			m.groups = append(m.groups, gt)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type GroupTestSuite struct {
+	suite.Suite
+}
+
+func (suite *GroupTestSuite) testAsGroupsEmpty() {
+	g := AsGroups()
+	suite.Zero(g.Len())
+	suite.Panics(func() {
+		g.Get(0)
+	})
+
+	var called bool
+	for range g.All() {
+		called = true
+	}
+
+	suite.False(called)
+}
+
+func (suite *GroupTestSuite) testAsGroupsNotEmpty() {
+	original := []Group{
+		{
+			Name:       "database",
+			Status:     StatusWarn,
+			Subsystems: AsSubsystems(Subsystem{Name: "db-primary"}),
+		},
+		{
+			Name:        "cache",
+			NonCritical: true,
+		},
+	}
+
+	g := AsGroups(original...)
+	suite.Equal(2, g.Len())
+	suite.Equal(original[0], g.Get(0))
+	suite.Equal(original[1], g.Get(1))
+
+	i := 0
+	for group := range g.All() {
+		suite.Equal(original[i], group)
+		i++
+	}
+
+	suite.Equal(2, i)
+
+	var count int
+	for range g.All() {
+		count++
+		break
+	}
+
+	suite.Equal(1, count, "All needs to honor early return")
+}
+
+func (suite *GroupTestSuite) testAsGroupsMarshalJSON() {
+	original := []Group{
+		{
+			Name:       "database",
+			Status:     StatusBad,
+			Subsystems: AsSubsystems(Subsystem{Name: "db-primary", Group: "database"}),
+		},
+	}
+
+	expected, err := json.Marshal(original)
+	suite.Require().NoError(err)
+
+	actual, err := AsGroups(original...).MarshalJSON()
+	suite.Require().NoError(err)
+	suite.JSONEq(string(expected), string(actual))
+}
+
+func (suite *GroupTestSuite) TestAsGroups() {
+	suite.Run("Empty", suite.testAsGroupsEmpty)
+	suite.Run("NotEmpty", suite.testAsGroupsNotEmpty)
+	suite.Run("MarshalJSON", suite.testAsGroupsMarshalJSON)
+}
+
+func TestGroup(t *testing.T) {
+	suite.Run(t, new(GroupTestSuite))
+}
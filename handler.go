@@ -4,10 +4,14 @@
 package haelu
 
 import (
-	"encoding/json"
+	"bytes"
 	"errors"
+	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 	"strconv"
+	"time"
 )
 
 // HealthResponseCoder is a strategy for turning a health Status into an HTTP response code.
@@ -31,6 +35,32 @@ func DefaultHealthResponseCoder(s Status) int {
 	}
 }
 
+const (
+	// DefaultHistoryParameter is the default name of the query parameter that requests
+	// subsystem history in a Handler's response.
+	DefaultHistoryParameter = "history"
+
+	// DefaultStatsParameter is the default name of the query parameter that requests
+	// subsystem statistics in a Handler's response.
+	DefaultStatsParameter = "stats"
+
+	// DefaultSubsystemPathValue is the default name of the path wildcard that selects
+	// a single subsystem, as in the pattern "GET /health/{name}".
+	DefaultSubsystemPathValue = "name"
+
+	// DefaultSubsystemParameter is the default name of the query parameter that selects
+	// subsystems. This parameter may be repeated to select several subsystems.
+	DefaultSubsystemParameter = "subsystem"
+
+	// DefaultWaitParameter is the default name of the query parameter that asks a
+	// Handler to wait for a change before responding.
+	DefaultWaitParameter = "wait"
+
+	// DefaultMaxWait is the default limit on how long a Handler waits for a change
+	// before responding.
+	DefaultMaxWait = time.Minute
+)
+
 // HandlerOption is a configurable option for customizing a health Handler.
 type HandlerOption interface {
 	apply(*Handler) error
@@ -60,16 +90,190 @@ func WithMonitor(m *Monitor) HandlerOption {
 	})
 }
 
+// WithKind restricts a Handler to the given kind, e.g. KindReadiness. The Handler's
+// response code and rendered overall status are taken from the status for that kind,
+// and only participating subsystems are rendered, along with only their history and
+// statistics. This allows several Handlers, one per kind, to be mounted for the same
+// Monitor.
+//
+// The kind must be known to the Monitor when the Handler is created, i.e. it must be
+// a standard kind or declared by one of the Monitor's subsystems. Otherwise, NewHandler
+// returns an error. Should the kind later become unknown, e.g. because the subsystems
+// declaring it were removed, the Handler reports StatusBad.
+//
+// If this option isn't used or is set to the empty string, the Handler renders the
+// Monitor's entire state.
+func WithKind(k Kind) HandlerOption {
+	return handlerOptionFunc(func(h *Handler) error {
+		h.kind = k
+		return nil
+	})
+}
+
+// WithHistoryParameter sets the name of the query parameter that, when set to a true
+// value such as "true" or "1", causes the Handler to include each subsystem's history
+// in its response.
+//
+// If this option isn't used, DefaultHistoryParameter is used. If set to the empty
+// string, history is never rendered.
+func WithHistoryParameter(name string) HandlerOption {
+	return handlerOptionFunc(func(h *Handler) error {
+		h.historyParameter = name
+		return nil
+	})
+}
+
+// WithStatsParameter sets the name of the query parameter that, when set to a true
+// value such as "true" or "1", causes the Handler to include each subsystem's Stats
+// in its response.
+//
+// If this option isn't used, DefaultStatsParameter is used. If set to the empty
+// string, statistics are never rendered.
+func WithStatsParameter(name string) HandlerOption {
+	return handlerOptionFunc(func(h *Handler) error {
+		h.statsParameter = name
+		return nil
+	})
+}
+
+// WithSubsystemPathValue sets the name of the path wildcard that selects a single subsystem.
+// When a Handler is registered with an http.ServeMux pattern containing this wildcard, such
+// as "GET /health/{name}", it renders only the selected Subsystem, and the response code
+// is derived from that subsystem's status. Unknown subsystems result in a 404.
+//
+// If this option isn't used, DefaultSubsystemPathValue is used. If set to the empty
+// string, subsystems are never selected by path.
+func WithSubsystemPathValue(name string) HandlerOption {
+	return handlerOptionFunc(func(h *Handler) error {
+		h.subsystemPathValue = name
+		return nil
+	})
+}
+
+// WithSubsystemParameter sets the name of the query parameter that selects subsystems.
+// When a request has one or more values for this parameter, the Handler renders only
+// the selected subsystems along with a status aggregated from them by the Monitor's
+// Aggregator. The response code is derived from that aggregated status. Unknown
+// subsystems result in a 404.
+//
+// If this option isn't used, DefaultSubsystemParameter is used. If set to the empty
+// string, subsystems are never selected by query.
+func WithSubsystemParameter(name string) HandlerOption {
+	return handlerOptionFunc(func(h *Handler) error {
+		h.subsystemParameter = name
+		return nil
+	})
+}
+
+// WithWaitParameter sets the name of the query parameter that turns a request into a
+// long poll. The parameter's value is the longest time to wait, either as a duration
+// such as "30s" or as a number of seconds. An empty value waits for the maximum time.
+//
+// A long poll blocks until the rendered representation no longer matches the client's
+// last seen ETag, given by the If-None-Match header, and then responds normally. If the
+// request has no If-None-Match header, the long poll blocks until the representation
+// differs from the one at the time of the request. If the wait elapses first, the
+// Handler responds with the current representation, which is a 304 when it still
+// matches If-None-Match.
+//
+// If this option isn't used, DefaultWaitParameter is used. If set to the empty
+// string, requests never wait.
+func WithWaitParameter(name string) HandlerOption {
+	return handlerOptionFunc(func(h *Handler) error {
+		h.waitParameter = name
+		return nil
+	})
+}
+
+// WithMaxWait sets the limit on how long a long poll waits for a change. Requests that
+// ask to wait longer are capped at this limit.
+//
+// If this option isn't used, DefaultMaxWait is used. If set to a nonpositive value,
+// requests never wait.
+func WithMaxWait(d time.Duration) HandlerOption {
+	return handlerOptionFunc(func(h *Handler) error {
+		h.maxWait = d
+		return nil
+	})
+}
+
+// Selection is the response body rendered by a Handler when several subsystems are
+// selected. Status is aggregated from the selected subsystems that are active.
+type Selection struct {
+	// Status is the aggregated status of the selected subsystems.
+	Status Status `json:"status" yaml:"status"`
+
+	// LastUpdate is the most recent update to any selected subsystem.
+	LastUpdate time.Time `json:"lastUpdate" yaml:"lastUpdate"`
+
+	// Subsystems are the selected subsystems, in the order they were requested.
+	Subsystems Subsystems `json:"subsystems" yaml:"subsystems"`
+}
+
+// rendering is the result of processing a request, prior to encoding.
+type rendering struct {
+	body   any
+	status Status
+
+	// lastModified is the time the body last changed. This is the zero time when
+	// the body can change without any update, in which case no Last-Modified header
+	// is written.
+	lastModified time.Time
+}
+
+// representation is a rendering encoded for a particular request.
+type representation struct {
+	rendering
+	contentType string
+	data        []byte
+	etag        string
+}
+
+// DetailedState is the response body rendered by a Handler when history or statistics
+// are requested.
+type DetailedState struct {
+	MonitorState `yaml:",inline"`
+
+	// History is the history of each subsystem, if requested.
+	History map[Name][]HistoryEntry `json:"history,omitempty" yaml:"history,omitempty"`
+
+	// Stats are the statistics for each subsystem, if requested.
+	Stats map[Name]Stats `json:"stats,omitempty" yaml:"stats,omitempty"`
+}
+
 // Handler is an HTTP handler that exposes health status. A Handler uses
 // a Monitor's State to render HTTP responses.
 type Handler struct {
-	coder   HealthResponseCoder
-	monitor *Monitor
+	coder            HealthResponseCoder
+	monitor          *Monitor
+	kind             Kind
+	historyParameter string
+	statsParameter   string
+
+	subsystemPathValue string
+	subsystemParameter string
+
+	encoders []mediaEncoder
+
+	waitParameter string
+	maxWait       time.Duration
 }
 
 // NewHandler constructs a new health Handler using the supplied set of options.
 func NewHandler(opts ...HandlerOption) (*Handler, error) {
-	h := new(Handler)
+	h := &Handler{
+		historyParameter: DefaultHistoryParameter,
+		statsParameter:   DefaultStatsParameter,
+
+		subsystemPathValue: DefaultSubsystemPathValue,
+		subsystemParameter: DefaultSubsystemParameter,
+
+		encoders: defaultEncoders(),
+
+		waitParameter: DefaultWaitParameter,
+		maxWait:       DefaultMaxWait,
+	}
+
 	for _, o := range opts {
 		if err := o.apply(h); err != nil {
 			return nil, err
@@ -80,6 +284,14 @@ func NewHandler(opts ...HandlerOption) (*Handler, error) {
 		return nil, errors.New("no monitor configured")
 	}
 
+	if len(h.encoders) == 0 {
+		return nil, errors.New("no encoders configured")
+	}
+
+	if _, ok := h.monitor.State().StatusOf(h.kind); !ok {
+		return nil, fmt.Errorf("the monitor has no kind [%s]", h.kind)
+	}
+
 	if h.coder == nil {
 		h.coder = DefaultHealthResponseCoder
 	}
@@ -88,21 +300,291 @@ func NewHandler(opts ...HandlerOption) (*Handler, error) {
 }
 
 // ServeHTTP returns an HTTP response that represents the most recent health update.
+//
+// Each response carries a strong ETag derived from the rendered representation, along
+// with a Last-Modified header unless statistics are rendered, as those change over
+// time. Last-Modified is the time of the Monitor's most recent state change, even when
+// only some subsystems are rendered. Requests whose If-None-Match or If-Modified-Since
+// preconditions show that the client already has the current representation receive
+// a 304 with no body, regardless of the health status. HEAD requests receive the same
+// headers and response code as GET requests, but no body. Any other method is
+// rejected with a 405.
+//
+// A request may also wait for the representation to change before responding, which
+// allows clients to long poll rather than repeatedly polling. See WithWaitParameter.
 func (h *Handler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		response.Header().Set("Allow", "GET, HEAD")
+		http.Error(response, fmt.Sprintf("method %s is not allowed", request.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	wait, err := h.waitFor(request)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// force clients to always revalidate and fetch the current value
 	response.Header().Set("Cache-Control", "no-cache")
-	state := h.monitor.State()
-	data, err := json.Marshal(state)
+	response.Header().Set("Vary", "Accept")
+
+	changed := h.monitor.Changed()
+	r, err := h.represent(request)
+	if err == nil && wait > 0 {
+		r, err = h.longPoll(request, r, changed, wait)
+	}
 
-	if err == nil {
-		response.Header().Set("Content-Type", "application/json")
-		response.Header().Set("Content-Length", strconv.Itoa(len(data)))
-		response.Header().Set("Last-Modified", state.LastUpdate.Format(http.TimeFormat))
-		response.WriteHeader(h.coder(state.Status))
-		_, err = response.Write(data)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, ErrNoSuchSubsystem) {
+			code = http.StatusNotFound
+		}
+
+		http.Error(response, err.Error(), code)
+		return
+	}
+
+	response.Header().Set("ETag", r.etag)
+	if !r.lastModified.IsZero() {
+		response.Header().Set("Last-Modified", r.lastModified.Format(http.TimeFormat))
+	}
+
+	if notModified(request, r.etag, r.lastModified) {
+		response.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	response.Header().Set("Content-Type", r.contentType)
+	response.Header().Set("Content-Length", strconv.Itoa(len(r.data)))
+	response.WriteHeader(h.coder(r.status))
+	if request.Method != http.MethodHead {
+		_, _ = response.Write(r.data)
+	}
+}
+
+// waitFor determines how long a request asks to wait for a change, capped at the
+// maximum wait. A zero duration indicates that the request does not wait.
+func (h *Handler) waitFor(request *http.Request) (time.Duration, error) {
+	if len(h.waitParameter) == 0 || h.maxWait <= 0 {
+		return 0, nil
+	}
+
+	values, ok := request.URL.Query()[h.waitParameter]
+	switch {
+	case !ok:
+		return 0, nil
+
+	case len(values[0]) == 0:
+		return h.maxWait, nil
 	}
 
+	d, err := time.ParseDuration(values[0])
+	if err != nil {
+		seconds, secondsErr := strconv.ParseUint(values[0], 10, 32)
+		if secondsErr != nil {
+			return 0, fmt.Errorf("invalid %s parameter [%s]: %w", h.waitParameter, values[0], err)
+		}
+
+		d = time.Duration(seconds) * time.Second
+	}
+
+	return min(max(d, 0), h.maxWait), nil
+}
+
+// represent renders and encodes the Monitor's current state for a request.
+func (h *Handler) represent(request *http.Request) (representation, error) {
+	state := h.monitor.State().ForKind(h.kind)
+	r, err := h.render(request, state)
 	if err != nil {
-		http.Error(response, err.Error(), http.StatusInternalServerError)
+		return representation{}, err
 	}
+
+	var (
+		me   = negotiate(h.encoders, request.Header.Get("Accept"))
+		data bytes.Buffer
+	)
+
+	if err := me.encoder.Encode(&data, r.body); err != nil {
+		return representation{}, err
+	}
+
+	return representation{
+		rendering:   r,
+		contentType: me.contentType,
+		data:        data.Bytes(),
+		etag:        entityTag(data.Bytes()),
+	}, nil
+}
+
+// longPoll waits until the representation for a request no longer matches the client's
+// last seen ETag, the wait elapses, or the request is canceled, then returns the most
+// recent representation. The given channel must have been obtained from the Monitor
+// before the given representation was rendered, so that no change is missed.
+func (h *Handler) longPoll(request *http.Request, r representation, changed <-chan struct{}, wait time.Duration) (representation, error) {
+	seen := request.Header.Get("If-None-Match")
+	if len(seen) == 0 {
+		seen = r.etag
+	}
+
+	timeCh, stop := h.monitor.newTimer(wait)
+	defer stop()
+
+	for etagMatches(seen, r.etag) {
+		select {
+		case <-request.Context().Done():
+			return r, nil
+
+		case <-timeCh:
+			return r, nil
+
+		case <-changed:
+			changed = h.monitor.Changed()
+			next, err := h.represent(request)
+			if err != nil {
+				return next, err
+			}
+
+			r = next
+		}
+	}
+
+	return r, nil
+}
+
+// render determines what to render for a request given the Monitor's state.
+func (h *Handler) render(request *http.Request, state MonitorState) (rendering, error) {
+	var byPath Name
+	if len(h.subsystemPathValue) > 0 {
+		byPath = Name(request.PathValue(h.subsystemPathValue))
+	}
+
+	var byQuery []string
+	if len(h.subsystemParameter) > 0 {
+		byQuery = request.URL.Query()[h.subsystemParameter]
+	}
+
+	switch {
+	case len(byPath) > 0 && len(byQuery) == 0:
+		return h.renderSubsystem(state, byPath)
+
+	case len(byPath) > 0 || len(byQuery) > 0:
+		names := make([]Name, 0, len(byQuery)+1)
+		if len(byPath) > 0 {
+			names = append(names, byPath)
+		}
+
+		for _, v := range byQuery {
+			names = append(names, Name(v))
+		}
+
+		return h.renderSelection(state, names)
+
+	default:
+		return h.renderState(request, state), nil
+	}
+}
+
+// renderState renders the entire state, along with any history or statistics
+// requested by query parameters.
+func (h *Handler) renderState(request *http.Request, state MonitorState) rendering {
+	r := rendering{
+		body:         state,
+		status:       state.Status,
+		lastModified: state.LastUpdate,
+	}
+
+	wantsHistory, wantsStats := queryFlag(request, h.historyParameter), queryFlag(request, h.statsParameter)
+	if wantsHistory || wantsStats {
+		detailed := DetailedState{
+			MonitorState: state,
+		}
+
+		if wantsHistory {
+			detailed.History = h.monitor.histories()
+		}
+
+		if wantsStats {
+			// statistics change over time, regardless of updates
+			detailed.Stats = h.monitor.allStats()
+			r.lastModified = time.Time{}
+		}
+
+		// only render details for the subsystems in the state, which may be restricted to a kind
+		rendered := make(map[Name]bool, state.Subsystems.Len())
+		for s := range state.Subsystems.All() {
+			rendered[s.Name] = true
+		}
+
+		maps.DeleteFunc(detailed.History, func(n Name, _ []HistoryEntry) bool { return !rendered[n] })
+		maps.DeleteFunc(detailed.Stats, func(n Name, _ Stats) bool { return !rendered[n] })
+		r.body = detailed
+	}
+
+	return r
+}
+
+// renderSubsystem renders a single subsystem.
+func (h *Handler) renderSubsystem(state MonitorState, n Name) (rendering, error) {
+	s, ok := state.Subsystems.find(n)
+	if !ok {
+		return rendering{}, fmt.Errorf("%w: no subsystem with the name [%s] is registered", ErrNoSuchSubsystem, n)
+	}
+
+	// a subsystem's effective status can change without an update to that subsystem,
+	// such as when it is paused or one of its dependencies changes
+	return rendering{
+		body:         s,
+		status:       s.Status,
+		lastModified: state.LastUpdate,
+	}, nil
+}
+
+// renderSelection renders several subsystems, along with a status aggregated
+// from the active subsystems among them.
+func (h *Handler) renderSelection(state MonitorState, names []Name) (rendering, error) {
+	var (
+		selected []Subsystem
+		active   []Subsystem
+		response Selection
+	)
+
+	for _, n := range names {
+		if slices.ContainsFunc(selected, func(s Subsystem) bool { return s.Name == n }) {
+			continue
+		}
+
+		s, ok := state.Subsystems.find(n)
+		if !ok {
+			return rendering{}, fmt.Errorf("%w: no subsystem with the name [%s] is registered", ErrNoSuchSubsystem, n)
+		}
+
+		selected = append(selected, s)
+		if s.active() {
+			active = append(active, s)
+		}
+
+		if s.LastUpdate.After(response.LastUpdate) {
+			response.LastUpdate = s.LastUpdate
+		}
+	}
+
+	response.Status = h.monitor.aggregator.Aggregate(Subsystems{ss: active})
+	response.Subsystems = Subsystems{ss: selected}
+	return rendering{
+		body:         response,
+		status:       response.Status,
+		lastModified: state.LastUpdate,
+	}, nil
+}
+
+// queryFlag tests if the given request set the given boolean query parameter to true.
+// An empty parameter name is never set.
+func queryFlag(request *http.Request, parameter string) bool {
+	if len(parameter) == 0 {
+		return false
+	}
+
+	v, _ := strconv.ParseBool(request.URL.Query().Get(parameter))
+	return v
 }
@@ -0,0 +1,705 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/xmidt-org/chronon"
+)
+
+type HandlerTestSuite struct {
+	suite.Suite
+
+	monitor *Monitor
+	mux     *http.ServeMux
+}
+
+func (suite *HandlerTestSuite) SetupTest() {
+	var err error
+	suite.monitor, err = NewMonitor(
+		WithSubsystems(
+			Definition{Name: "db", Status: StatusBad},
+			Definition{Name: "cache", Status: StatusWarn, NonCritical: true},
+			Definition{Name: "queue"},
+		),
+	)
+
+	suite.Require().NoError(err)
+
+	h, err := NewHandler(WithMonitor(suite.monitor))
+	suite.Require().NoError(err)
+
+	suite.mux = http.NewServeMux()
+	suite.mux.Handle("GET /health", h)
+	suite.mux.Handle("GET /health/{name}", h)
+}
+
+func (suite *HandlerTestSuite) serve(target string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	suite.mux.ServeHTTP(response, httptest.NewRequest(http.MethodGet, target, nil))
+	return response
+}
+
+func (suite *HandlerTestSuite) assertJSON(expected any, response *httptest.ResponseRecorder) {
+	data, err := json.Marshal(expected)
+	suite.Require().NoError(err)
+	suite.Equal("application/json", response.Header().Get("Content-Type"))
+	suite.JSONEq(string(data), response.Body.String())
+}
+
+func (suite *HandlerTestSuite) TestState() {
+	response := suite.serve("/health")
+	suite.Equal(http.StatusInternalServerError, response.Code)
+	suite.assertJSON(suite.monitor.State(), response)
+}
+
+func (suite *HandlerTestSuite) TestSubsystem() {
+	for _, testCase := range []struct {
+		name         Name
+		expectedCode int
+	}{
+		{name: "db", expectedCode: http.StatusInternalServerError},
+		{name: "cache", expectedCode: http.StatusTooManyRequests},
+		{name: "queue", expectedCode: http.StatusOK},
+	} {
+		suite.Run(string(testCase.name), func() {
+			response := suite.serve("/health/" + string(testCase.name))
+			suite.Equal(testCase.expectedCode, response.Code)
+
+			s, err := suite.monitor.Subsystem(testCase.name)
+			suite.Require().NoError(err)
+			suite.assertJSON(s, response)
+		})
+	}
+
+	suite.Run("Unknown", func() {
+		suite.Equal(http.StatusNotFound, suite.serve("/health/nosuch").Code)
+	})
+}
+
+func (suite *HandlerTestSuite) TestSelection() {
+	state := suite.monitor.State()
+	queue, _ := state.Subsystems.find("queue")
+	cache, _ := state.Subsystems.find("cache")
+	db, _ := state.Subsystems.find("db")
+
+	suite.Run("Aggregated", func() {
+		response := suite.serve("/health?subsystem=queue&subsystem=cache&subsystem=queue")
+		suite.Equal(http.StatusTooManyRequests, response.Code)
+		suite.assertJSON(
+			Selection{
+				Status:     StatusWarn,
+				LastUpdate: state.LastUpdate,
+				Subsystems: AsSubsystems(queue, cache),
+			},
+			response,
+		)
+	})
+
+	suite.Run("WithPath", func() {
+		response := suite.serve("/health/queue?subsystem=db")
+		suite.Equal(http.StatusInternalServerError, response.Code)
+		suite.assertJSON(
+			Selection{
+				Status:     StatusBad,
+				LastUpdate: state.LastUpdate,
+				Subsystems: AsSubsystems(queue, db),
+			},
+			response,
+		)
+	})
+
+	suite.Run("Paused", func() {
+		suite.Require().NoError(suite.monitor.Pause("db"))
+		response := suite.serve("/health?subsystem=queue&subsystem=db")
+		suite.Equal(http.StatusOK, response.Code)
+	})
+
+	suite.Run("Unknown", func() {
+		suite.Equal(http.StatusNotFound, suite.serve("/health?subsystem=queue&subsystem=nosuch").Code)
+	})
+}
+
+// details decodes the top-level fields of a response body.
+func (suite *HandlerTestSuite) details(response *httptest.ResponseRecorder) (fields map[string]json.RawMessage) {
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &fields))
+	return
+}
+
+func (suite *HandlerTestSuite) TestHistory() {
+	m, err := NewMonitor(
+		WithSubsystems(
+			Definition{Name: "db"},
+			Definition{Name: "limited", HistorySize: 2},
+			Definition{Name: "disabled", HistorySize: -1},
+		),
+	)
+
+	suite.Require().NoError(err)
+	for _, n := range []Name{"db", "limited", "disabled"} {
+		u, err := m.Get(n)
+		suite.Require().NoError(err)
+		u.Update(StatusWarn, nil)
+		u.Update(StatusBad, errors.New("expected"))
+	}
+
+	h, err := NewHandler(WithMonitor(m))
+	suite.Require().NoError(err)
+	suite.mux = http.NewServeMux()
+	suite.mux.Handle("GET /health", h)
+
+	response := suite.serve("/health?history=true")
+	suite.Equal(http.StatusInternalServerError, response.Code)
+
+	var body struct {
+		Status  Status                  `json:"status"`
+		History map[Name][]HistoryEntry `json:"history"`
+	}
+
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &body))
+	suite.Equal(StatusBad, body.Status)
+
+	expected, err := m.History("db")
+	suite.Require().NoError(err)
+	suite.Len(expected, 3)
+	suite.Equal(expected, body.History["db"])
+
+	// only the most recent entries are retained
+	suite.Require().Len(body.History["limited"], 2)
+	suite.Equal(StatusWarn, body.History["limited"][0].Status)
+	suite.Equal(StatusBad, body.History["limited"][1].Status)
+	suite.Equal("expected", body.History["limited"][1].Error)
+
+	suite.NotContains(body.History, Name("disabled"))
+	suite.NotContains(suite.details(suite.serve("/health?history=false")), "history")
+
+	suite.Run("Disabled", func() {
+		h, err := NewHandler(WithMonitor(m), WithHistoryParameter(""))
+		suite.Require().NoError(err)
+		suite.mux = http.NewServeMux()
+		suite.mux.Handle("GET /health", h)
+
+		suite.NotContains(suite.details(suite.serve("/health?history=true")), "history")
+	})
+}
+
+func (suite *HandlerTestSuite) TestStats() {
+	response := suite.serve("/health?stats=true")
+	suite.Equal(http.StatusInternalServerError, response.Code)
+	suite.Empty(response.Header().Get("Last-Modified"))
+
+	var body struct {
+		Status Status `json:"status"`
+		Stats  map[Name]struct {
+			Transitions  int     `json:"transitions"`
+			Availability float64 `json:"availability"`
+		} `json:"stats"`
+	}
+
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &body))
+	suite.Equal(StatusBad, body.Status)
+	suite.Len(body.Stats, 3)
+	suite.Contains(body.Stats, Name("db"))
+	suite.Contains(body.Stats, Name("cache"))
+	suite.Contains(body.Stats, Name("queue"))
+
+	// statistics change without updates, so If-Modified-Since never applies
+	request := httptest.NewRequest(http.MethodGet, "/health?stats=true", nil)
+	request.Header.Set("If-Modified-Since", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	response = httptest.NewRecorder()
+	suite.mux.ServeHTTP(response, request)
+	suite.Equal(http.StatusInternalServerError, response.Code)
+
+	suite.NotContains(suite.details(suite.serve("/health?stats=0")), "stats")
+
+	suite.Run("Disabled", func() {
+		h, err := NewHandler(WithMonitor(suite.monitor), WithStatsParameter(""))
+		suite.Require().NoError(err)
+		suite.mux = http.NewServeMux()
+		suite.mux.Handle("GET /health", h)
+
+		response := suite.serve("/health?stats=true")
+		suite.NotEmpty(response.Header().Get("Last-Modified"))
+		suite.NotContains(suite.details(response), "stats")
+	})
+}
+
+func (suite *HandlerTestSuite) TestKind() {
+	m, err := NewMonitor(
+		WithSubsystems(
+			Definition{Name: "process"},
+			Definition{Name: "db", Status: StatusBad, Kinds: []Kind{KindReadiness}},
+			Definition{Name: "migrations", Status: StatusWarn, Kinds: []Kind{KindStartup, "batch"}},
+		),
+	)
+
+	suite.Require().NoError(err)
+	for _, testCase := range []struct {
+		kind         Kind
+		expectedCode int
+		expected     []string
+	}{
+		{kind: KindLiveness, expectedCode: http.StatusOK, expected: []string{"process"}},
+		{kind: KindReadiness, expectedCode: http.StatusInternalServerError, expected: []string{"process", "db"}},
+		{kind: KindStartup, expectedCode: http.StatusTooManyRequests, expected: []string{"process", "migrations"}},
+		{kind: "batch", expectedCode: http.StatusTooManyRequests, expected: []string{"process", "migrations"}},
+	} {
+		suite.Run(string(testCase.kind), func() {
+			h, err := NewHandler(WithMonitor(m), WithKind(testCase.kind))
+			suite.Require().NoError(err)
+
+			response := httptest.NewRecorder()
+			h.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/health?history=true&stats=true", nil))
+			suite.Equal(testCase.expectedCode, response.Code)
+
+			var body struct {
+				Subsystems []struct {
+					Name string `json:"name"`
+				} `json:"subsystems"`
+
+				History map[string]json.RawMessage `json:"history"`
+				Stats   map[string]json.RawMessage `json:"stats"`
+			}
+
+			suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &body))
+			var names []string
+			for _, s := range body.Subsystems {
+				names = append(names, s.Name)
+			}
+
+			suite.Equal(testCase.expected, names)
+			suite.ElementsMatch(testCase.expected, slices.Collect(maps.Keys(body.History)))
+			suite.ElementsMatch(testCase.expected, slices.Collect(maps.Keys(body.Stats)))
+		})
+	}
+
+	suite.Run("Unknown", func() {
+		h, err := NewHandler(WithMonitor(m), WithKind("readyness"))
+		suite.Error(err)
+		suite.Nil(h)
+	})
+
+	suite.Run("Removed", func() {
+		h, err := NewHandler(WithMonitor(m), WithKind("batch"))
+		suite.Require().NoError(err)
+		suite.Require().NoError(m.Remove("migrations"))
+
+		response := httptest.NewRecorder()
+		h.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/health", nil))
+		suite.Equal(http.StatusInternalServerError, response.Code)
+	})
+}
+
+func (suite *HandlerTestSuite) TestSelectionDisabled() {
+	h, err := NewHandler(
+		WithMonitor(suite.monitor),
+		WithSubsystemPathValue(""),
+		WithSubsystemParameter(""),
+	)
+
+	suite.Require().NoError(err)
+	suite.mux = http.NewServeMux()
+	suite.mux.Handle("GET /health/{name}", h)
+
+	response := suite.serve("/health/queue?subsystem=queue")
+	suite.Equal(http.StatusInternalServerError, response.Code)
+	suite.assertJSON(suite.monitor.State(), response)
+}
+
+func (suite *HandlerTestSuite) TestNegotiation() {
+	for _, testCase := range []struct {
+		accept              string
+		expectedContentType string
+		expectedPrefix      string
+	}{
+		{accept: "", expectedContentType: "application/json", expectedPrefix: "{"},
+		{accept: "*/*", expectedContentType: "application/json", expectedPrefix: "{"},
+		{accept: "application/yaml", expectedContentType: "application/yaml", expectedPrefix: "status: bad\n"},
+		{accept: "text/plain", expectedContentType: "text/plain; charset=utf-8", expectedPrefix: "status: bad\n"},
+		{accept: "text/html,application/xhtml+xml,*/*;q=0.8", expectedContentType: "text/html; charset=utf-8", expectedPrefix: "<!DOCTYPE html>"},
+		{accept: "image/png", expectedContentType: "application/json", expectedPrefix: "{"},
+	} {
+		suite.Run(testCase.accept, func() {
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/health", nil)
+			request.Header.Set("Accept", testCase.accept)
+			suite.mux.ServeHTTP(response, request)
+
+			suite.Equal(http.StatusInternalServerError, response.Code)
+			suite.Equal(testCase.expectedContentType, response.Header().Get("Content-Type"))
+			suite.Equal("Accept", response.Header().Get("Vary"))
+			suite.True(strings.HasPrefix(response.Body.String(), testCase.expectedPrefix), response.Body.String())
+		})
+	}
+}
+
+func (suite *HandlerTestSuite) TestCustomEncoder() {
+	h, err := NewHandler(
+		WithMonitor(suite.monitor),
+		WithEncoder("application/json", nil),
+		WithEncoder("text/csv; charset=utf-8", EncoderFunc(func(w io.Writer, _ any) error {
+			_, err := io.WriteString(w, "custom")
+			return err
+		})),
+	)
+
+	suite.Require().NoError(err)
+	response := httptest.NewRecorder()
+	h.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/health", nil))
+	suite.Equal("application/yaml", response.Header().Get("Content-Type"))
+
+	response = httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/health", nil)
+	request.Header.Set("Accept", "text/csv")
+	h.ServeHTTP(response, request)
+	suite.Equal("text/csv; charset=utf-8", response.Header().Get("Content-Type"))
+	suite.Equal("custom", response.Body.String())
+}
+
+func (suite *HandlerTestSuite) TestNoEncoders() {
+	_, err := NewHandler(
+		WithMonitor(suite.monitor),
+		WithEncoder("application/json", nil),
+		WithEncoder("application/yaml", nil),
+		WithEncoder("text/plain", nil),
+		WithEncoder("text/html", nil),
+	)
+
+	suite.Error(err)
+
+	_, err = NewHandler(
+		WithMonitor(suite.monitor),
+		WithEncoder("this is not a media type/", JSONEncoder()),
+	)
+
+	suite.Error(err)
+}
+
+func (suite *HandlerTestSuite) TestConditional() {
+	first := suite.serve("/health")
+	etag := first.Header().Get("ETag")
+	lastModified := first.Header().Get("Last-Modified")
+	suite.NotEmpty(etag)
+	suite.Equal(etag, suite.serve("/health").Header().Get("ETag"))
+
+	conditional := func(header, value string) *httptest.ResponseRecorder {
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/health", nil)
+		request.Header.Set(header, value)
+		suite.mux.ServeHTTP(response, request)
+		return response
+	}
+
+	suite.Run("IfNoneMatch", func() {
+		response := conditional("If-None-Match", `"other", `+etag)
+		suite.Equal(http.StatusNotModified, response.Code)
+		suite.Equal(etag, response.Header().Get("ETag"))
+		suite.Empty(response.Body.String())
+
+		suite.Equal(http.StatusNotModified, conditional("If-None-Match", "W/"+etag).Code)
+		suite.Equal(http.StatusNotModified, conditional("If-None-Match", "*").Code)
+		suite.Equal(http.StatusInternalServerError, conditional("If-None-Match", `"other"`).Code)
+	})
+
+	suite.Run("IfModifiedSince", func() {
+		suite.Equal(http.StatusNotModified, conditional("If-Modified-Since", lastModified).Code)
+		suite.Equal(
+			http.StatusInternalServerError,
+			conditional("If-Modified-Since", time.Time{}.Add(time.Hour).Format(http.TimeFormat)).Code,
+		)
+	})
+
+	suite.Run("Representation", func() {
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/health", nil)
+		request.Header.Set("Accept", "text/plain")
+		request.Header.Set("If-None-Match", etag)
+		suite.mux.ServeHTTP(response, request)
+		suite.Equal(http.StatusInternalServerError, response.Code)
+		suite.NotEqual(etag, response.Header().Get("ETag"))
+	})
+
+	suite.Run("Changed", func() {
+		u, err := suite.monitor.Get("db")
+		suite.Require().NoError(err)
+		u.Update(StatusGood, nil)
+
+		response := conditional("If-None-Match", etag)
+		suite.Equal(http.StatusTooManyRequests, response.Code)
+		suite.NotEqual(etag, response.Header().Get("ETag"))
+	})
+}
+
+func (suite *HandlerTestSuite) TestConditionalEffectiveStatus() {
+	clock, _ := suite.useFakeClock(
+		Definition{Name: "net"},
+		Definition{Name: "cache", DependsOn: []Name{"net"}},
+		Definition{Name: "queue"},
+	)
+
+	conditional := func(target string, since time.Time) *httptest.ResponseRecorder {
+		response := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, target, nil)
+		request.Header.Set("If-Modified-Since", since.Format(http.TimeFormat))
+		suite.mux.ServeHTTP(response, request)
+		return response
+	}
+
+	for _, target := range []string{"/health/cache", "/health?subsystem=cache&subsystem=queue"} {
+		suite.Run(target, func() {
+			since := clock.Now()
+			suite.Equal(http.StatusNotModified, conditional(target, since).Code)
+
+			clock.Add(time.Minute)
+			net, err := suite.monitor.Get("net")
+			suite.Require().NoError(err)
+			net.Update(StatusBad, nil)
+
+			suite.Equal(http.StatusInternalServerError, conditional(target, since).Code)
+			since = clock.Now()
+			suite.Equal(http.StatusNotModified, conditional(target, since).Code)
+
+			clock.Add(time.Minute)
+			suite.Require().NoError(suite.monitor.Pause("cache"))
+			response := conditional(target, since)
+			suite.NotEqual(http.StatusNotModified, response.Code)
+			suite.Equal(clock.Now().UTC().Format(http.TimeFormat), response.Header().Get("Last-Modified"))
+
+			suite.Require().NoError(suite.monitor.Resume("cache"))
+			net.Update(StatusGood, nil)
+		})
+	}
+}
+
+func (suite *HandlerTestSuite) TestHead() {
+	expected := suite.serve("/health")
+	response := httptest.NewRecorder()
+	suite.mux.ServeHTTP(response, httptest.NewRequest(http.MethodHead, "/health", nil))
+	suite.Equal(expected.Code, response.Code)
+	suite.Equal(expected.Header().Get("ETag"), response.Header().Get("ETag"))
+	suite.Equal(expected.Header().Get("Content-Length"), response.Header().Get("Content-Length"))
+	suite.Empty(response.Body.String())
+}
+
+func (suite *HandlerTestSuite) TestMethodNotAllowed() {
+	h, err := NewHandler(WithMonitor(suite.monitor))
+	suite.Require().NoError(err)
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodDelete} {
+		suite.Run(method, func() {
+			response := httptest.NewRecorder()
+			h.ServeHTTP(response, httptest.NewRequest(method, "/health", nil))
+			suite.Equal(http.StatusMethodNotAllowed, response.Code)
+			suite.Equal("GET, HEAD", response.Header().Get("Allow"))
+		})
+	}
+}
+
+func (suite *HandlerTestSuite) longPoll(target, etag string) <-chan *httptest.ResponseRecorder {
+	responses := make(chan *httptest.ResponseRecorder, 1)
+	request := httptest.NewRequest(http.MethodGet, target, nil)
+	if len(etag) > 0 {
+		request.Header.Set("If-None-Match", etag)
+	}
+
+	go func() {
+		response := httptest.NewRecorder()
+		suite.mux.ServeHTTP(response, request)
+		responses <- response
+	}()
+
+	return responses
+}
+
+func (suite *HandlerTestSuite) receiveResponse(responses <-chan *httptest.ResponseRecorder) *httptest.ResponseRecorder {
+	select {
+	case response := <-responses:
+		return response
+
+	case <-time.After(5 * time.Second):
+		suite.Require().Fail("no response received")
+		return nil
+	}
+}
+
+// useFakeClock replaces the monitor and handlers under test with ones that use
+// a fake clock and the given subsystems. The returned channel receives the duration
+// of each created timer, which allows tests to wait until a long poll is waiting.
+func (suite *HandlerTestSuite) useFakeClock(ds ...Definition) (*chronon.FakeClock, <-chan time.Duration) {
+	var (
+		clock  = chronon.NewFakeClock(time.Now())
+		timers = make(chan time.Duration, 10)
+		err    error
+	)
+
+	suite.monitor, err = NewMonitor(
+		WithSubsystems(ds...),
+		monitorOptionFunc(func(m *Monitor) error {
+			m.now = clock.Now
+			m.newTimer = notifyingFakeTimer(clock, timers)
+			return nil
+		}),
+	)
+
+	suite.Require().NoError(err)
+	h, err := NewHandler(WithMonitor(suite.monitor))
+	suite.Require().NoError(err)
+
+	suite.mux = http.NewServeMux()
+	suite.mux.Handle("GET /health", h)
+	suite.mux.Handle("GET /health/{name}", h)
+	return clock, timers
+}
+
+// receiveTimer waits for a long poll to create its timer.
+func (suite *HandlerTestSuite) receiveTimer(timers <-chan time.Duration) time.Duration {
+	select {
+	case d := <-timers:
+		return d
+
+	case <-time.After(time.Second):
+		suite.Require().Fail("no timer created")
+		return 0
+	}
+}
+
+func (suite *HandlerTestSuite) TestLongPoll() {
+	clock, timers := suite.useFakeClock(
+		Definition{Name: "db", Status: StatusBad},
+		Definition{Name: "cache", Status: StatusWarn, NonCritical: true},
+		Definition{Name: "queue"},
+	)
+	u, err := suite.monitor.Get("db")
+	suite.Require().NoError(err)
+	etag := suite.serve("/health").Header().Get("ETag")
+
+	suite.Run("Changed", func() {
+		responses := suite.longPoll("/health?wait=30s", etag)
+		suite.Equal(30*time.Second, suite.receiveTimer(timers))
+		u.Update(StatusGood, nil)
+
+		response := suite.receiveResponse(responses)
+		suite.Equal(http.StatusTooManyRequests, response.Code)
+		suite.NotEqual(etag, response.Header().Get("ETag"))
+		etag = response.Header().Get("ETag")
+	})
+
+	suite.Run("AlreadyChanged", func() {
+		response := suite.receiveResponse(suite.longPoll("/health?wait=30", `"stale"`))
+		suite.Equal(http.StatusTooManyRequests, response.Code)
+		suite.Equal(etag, response.Header().Get("ETag"))
+		suite.Equal(30*time.Second, suite.receiveTimer(timers))
+	})
+
+	suite.Run("IrrelevantChange", func() {
+		queue := suite.serve("/health/queue").Header().Get("ETag")
+		responses := suite.longPoll("/health/queue?wait=1m", queue)
+		suite.Equal(time.Minute, suite.receiveTimer(timers))
+		u.Update(StatusBad, nil)
+
+		clock.Add(time.Minute)
+		response := suite.receiveResponse(responses)
+		suite.Equal(http.StatusNotModified, response.Code)
+		suite.Equal(queue, response.Header().Get("ETag"))
+	})
+
+	suite.Run("Timeout", func() {
+		current := suite.serve("/health").Header().Get("ETag")
+		responses := suite.longPoll("/health?wait=10s", current)
+		suite.Equal(10*time.Second, suite.receiveTimer(timers))
+		clock.Add(10 * time.Second)
+
+		response := suite.receiveResponse(responses)
+		suite.Equal(http.StatusNotModified, response.Code)
+
+		responses = suite.longPoll("/health?wait=10s", "")
+		suite.Equal(10*time.Second, suite.receiveTimer(timers))
+		clock.Add(10 * time.Second)
+
+		response = suite.receiveResponse(responses)
+		suite.Equal(http.StatusInternalServerError, response.Code)
+		suite.Equal(current, response.Header().Get("ETag"))
+	})
+
+	suite.Run("NoETag", func() {
+		current := suite.serve("/health").Header().Get("ETag")
+		responses := suite.longPoll("/health?wait=", "")
+		suite.Equal(DefaultMaxWait, suite.receiveTimer(timers))
+		u.Update(StatusGood, nil)
+
+		response := suite.receiveResponse(responses)
+		suite.Equal(http.StatusTooManyRequests, response.Code)
+		suite.NotEqual(current, response.Header().Get("ETag"))
+	})
+
+	suite.Run("Canceled", func() {
+		current := suite.serve("/health").Header().Get("ETag")
+		ctx, cancel := context.WithCancel(context.Background())
+		request := httptest.NewRequestWithContext(ctx, http.MethodGet, "/health?wait=1m", nil)
+		request.Header.Set("If-None-Match", current)
+
+		responses := make(chan *httptest.ResponseRecorder, 1)
+		go func() {
+			response := httptest.NewRecorder()
+			suite.mux.ServeHTTP(response, request)
+			responses <- response
+		}()
+
+		suite.Equal(time.Minute, suite.receiveTimer(timers))
+		cancel()
+		suite.Equal(http.StatusNotModified, suite.receiveResponse(responses).Code)
+	})
+
+	suite.Run("Invalid", func() {
+		suite.Equal(http.StatusBadRequest, suite.serve("/health?wait=soon").Code)
+	})
+}
+
+func (suite *HandlerTestSuite) TestWaitFor() {
+	h, err := NewHandler(WithMonitor(suite.monitor), WithMaxWait(time.Minute))
+	suite.Require().NoError(err)
+
+	for _, testCase := range []struct {
+		target   string
+		expected time.Duration
+	}{
+		{target: "/health", expected: 0},
+		{target: "/health?wait=", expected: time.Minute},
+		{target: "/health?wait=15s", expected: 15 * time.Second},
+		{target: "/health?wait=15", expected: 15 * time.Second},
+		{target: "/health?wait=-1s", expected: 0},
+		{target: "/health?wait=1h", expected: time.Minute},
+	} {
+		suite.Run(testCase.target, func() {
+			d, err := h.waitFor(httptest.NewRequest(http.MethodGet, testCase.target, nil))
+			suite.NoError(err)
+			suite.Equal(testCase.expected, d)
+		})
+	}
+
+	for _, o := range []HandlerOption{WithWaitParameter(""), WithMaxWait(0)} {
+		h, err := NewHandler(WithMonitor(suite.monitor), o)
+		suite.Require().NoError(err)
+
+		d, err := h.waitFor(httptest.NewRequest(http.MethodGet, "/health?wait=foo", nil))
+		suite.NoError(err)
+		suite.Zero(d)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import "time"
+
+// DefaultHistorySize is the default maximum number of history entries retained
+// for each subsystem.
+const DefaultHistorySize = 10
+
+// HistoryEntry records a single transition in a subsystem's observed status or error.
+type HistoryEntry struct {
+	// Timestamp is the time at which the transition occurred.
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+
+	// Status is the subsystem's observed status as of this transition.
+	Status Status `json:"status" yaml:"status"`
+
+	// Error is the text of the subsystem's error as of this transition, if any.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// history is a fixed-size ring buffer of HistoryEntry objects.
+type history struct {
+	entries []HistoryEntry
+	next    int
+	full    bool
+}
+
+// newHistory creates a history with the given maximum size. If size is nonpositive,
+// this function returns nil, which disables history.
+func newHistory(size int) *history {
+	if size < 1 {
+		return nil
+	}
+
+	return &history{
+		entries: make([]HistoryEntry, size),
+	}
+}
+
+// last returns the most recent entry, if one exists.
+func (h *history) last() (HistoryEntry, bool) {
+	switch {
+	case h.next > 0:
+		return h.entries[h.next-1], true
+
+	case h.full:
+		return h.entries[len(h.entries)-1], true
+
+	default:
+		return HistoryEntry{}, false
+	}
+}
+
+// record appends an entry if it differs in status or error from the most recent
+// entry. When full, the oldest entry is discarded.
+func (h *history) record(e HistoryEntry) {
+	if last, ok := h.last(); ok && last.Status == e.Status && last.Error == e.Error {
+		return
+	}
+
+	h.entries[h.next] = e
+	h.next++
+	if h.next >= len(h.entries) {
+		h.next = 0
+		h.full = true
+	}
+}
+
+// snapshot returns a copy of the entries, from oldest to newest.
+func (h *history) snapshot() (s []HistoryEntry) {
+	if h.full {
+		s = append(s, h.entries[h.next:]...)
+	}
+
+	return append(s, h.entries[:h.next]...)
+}
+
+// initializeHistory creates this subsystem's history, applying the default size, and
+// records the initial state.
+func (sst *subsystemTracker) initializeHistory(defaultSize int) {
+	if sst.definition.HistorySize == 0 {
+		sst.definition.HistorySize = defaultSize
+	}
+
+	sst.history = newHistory(sst.definition.HistorySize)
+	sst.unsafeRecordHistory()
+}
+
+// unsafeRecordHistory appends the current observed status and error of this subsystem
+// to its history, if history is enabled.
+//
+// This method must be executed under the monitor lock.
+func (sst *subsystemTracker) unsafeRecordHistory() {
+	if sst.history == nil {
+		return
+	}
+
+	e := HistoryEntry{
+		Timestamp: sst.current.LastUpdate,
+		Status:    sst.current.ObservedStatus,
+	}
+
+	if sst.current.LastError != nil {
+		e.Error = sst.current.LastError.Error()
+	}
+
+	sst.history.record(e)
+}
+
+// History returns the recorded transitions for the given subsystem, from oldest
+// to newest. If history is disabled for the subsystem, this method returns no
+// entries.
+//
+// If no such subsystem exists, this method returns an error that wraps ErrNoSuchSubsystem.
+func (m *Monitor) History(n Name) ([]HistoryEntry, error) {
+	defer m.lock.Unlock()
+	m.lock.Lock()
+
+	sst, err := m.find(n)
+	if err != nil || sst.history == nil {
+		return nil, err
+	}
+
+	return sst.history.snapshot(), nil
+}
+
+// histories returns the recorded transitions for every subsystem that has
+// history enabled.
+func (m *Monitor) histories() map[Name][]HistoryEntry {
+	defer m.lock.Unlock()
+	m.lock.Lock()
+
+	h := make(map[Name][]HistoryEntry, len(m.trackers))
+	for _, sst := range m.trackers {
+		if sst.history != nil {
+			h[sst.definition.Name] = sst.history.snapshot()
+		}
+	}
+
+	return h
+}
+
+// WithDefaultHistorySize sets the default maximum number of history entries retained
+// for subsystems that do not define their own HistorySize. If unset, DefaultHistorySize
+// is used. If nonpositive, history is disabled by default.
+func WithDefaultHistorySize(size int) MonitorOption {
+	return monitorOptionFunc(func(m *Monitor) error {
+		m.defaultHistorySize = size
+		return nil
+	})
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HistoryTestSuite struct {
+	suite.Suite
+}
+
+func (suite *HistoryTestSuite) entry(seconds int, s Status, err string) HistoryEntry {
+	return HistoryEntry{
+		Timestamp: time.Unix(int64(seconds), 0),
+		Status:    s,
+		Error:     err,
+	}
+}
+
+func (suite *HistoryTestSuite) TestDisabled() {
+	suite.Nil(newHistory(0))
+	suite.Nil(newHistory(-1))
+}
+
+func (suite *HistoryTestSuite) TestEmpty() {
+	h := newHistory(3)
+	suite.Require().NotNil(h)
+	suite.Empty(h.snapshot())
+
+	_, ok := h.last()
+	suite.False(ok)
+}
+
+func (suite *HistoryTestSuite) TestRecord() {
+	h := newHistory(3)
+	suite.Require().NotNil(h)
+
+	h.record(suite.entry(1, StatusGood, ""))
+	h.record(suite.entry(2, StatusGood, "")) // no transition
+	h.record(suite.entry(3, StatusWarn, ""))
+	h.record(suite.entry(4, StatusWarn, "timeout"))
+	suite.Equal(
+		[]HistoryEntry{
+			suite.entry(1, StatusGood, ""),
+			suite.entry(3, StatusWarn, ""),
+			suite.entry(4, StatusWarn, "timeout"),
+		},
+		h.snapshot(),
+	)
+
+	last, ok := h.last()
+	suite.True(ok)
+	suite.Equal(suite.entry(4, StatusWarn, "timeout"), last)
+
+	// the oldest entries are discarded
+	h.record(suite.entry(5, StatusBad, "timeout"))
+	h.record(suite.entry(6, StatusGood, ""))
+	suite.Equal(
+		[]HistoryEntry{
+			suite.entry(4, StatusWarn, "timeout"),
+			suite.entry(5, StatusBad, "timeout"),
+			suite.entry(6, StatusGood, ""),
+		},
+		h.snapshot(),
+	)
+
+	last, ok = h.last()
+	suite.True(ok)
+	suite.Equal(suite.entry(6, StatusGood, ""), last)
+}
+
+func TestHistory(t *testing.T) {
+	suite.Run(t, new(HistoryTestSuite))
+}
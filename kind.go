@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"encoding/json"
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Kind identifies a view of a Monitor's overall health, such as the liveness or
+// readiness of an application.
+type Kind string
+
+const (
+	// KindLiveness is the view of whether an application is running and should
+	// not be restarted.
+	KindLiveness Kind = "liveness"
+
+	// KindReadiness is the view of whether an application can accept traffic.
+	KindReadiness Kind = "readiness"
+
+	// KindStartup is the view of whether an application has finished starting.
+	KindStartup Kind = "startup"
+)
+
+// StandardKinds returns the kinds for which a Monitor always computes a status.
+func StandardKinds() []Kind {
+	return []Kind{KindLiveness, KindReadiness, KindStartup}
+}
+
+// KindStatuses is an immutable set of overall statuses, one for each kind.
+type KindStatuses struct {
+	ks map[Kind]Status
+}
+
+// AsKindStatuses creates an immutable KindStatuses from a map of kinds to their
+// overall statuses. The returned KindStatuses will be a copy of the given map.
+func AsKindStatuses(statuses map[Kind]Status) (ks KindStatuses) {
+	if len(statuses) > 0 {
+		ks.ks = maps.Clone(statuses)
+	}
+
+	return
+}
+
+// Len returns the count of kinds in this set.
+func (ks KindStatuses) Len() int {
+	return len(ks.ks)
+}
+
+// All provides an iterator over each kind and its status, in order of kind.
+func (ks KindStatuses) All() iter.Seq2[Kind, Status] {
+	return func(f func(Kind, Status) bool) {
+		for _, k := range slices.Sorted(maps.Keys(ks.ks)) {
+			if !f(k, ks.ks[k]) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON marshals this set as an object of kinds to statuses.
+func (ks KindStatuses) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ks.ks)
+}
+
+// MarshalYAML marshals this set as a mapping of kinds to statuses.
+func (ks KindStatuses) MarshalYAML() (any, error) {
+	return ks.ks, nil
+}
+
+// StatusOf returns the overall status for the given kind, along with whether a status
+// was computed for that kind. If k is empty, this method returns the overall status of
+// the Monitor. If no status was computed for k, e.g. because k is misspelled, this method
+// returns StatusBad and false, so that an unknown kind never appears healthy.
+func (ms MonitorState) StatusOf(k Kind) (Status, bool) {
+	if len(k) == 0 {
+		return ms.Status, true
+	}
+
+	s, ok := ms.Kinds.ks[k]
+	if !ok {
+		return StatusBad, false
+	}
+
+	return s, true
+}
+
+// ForKind returns a copy of this state as seen by the given kind. The copy's overall
+// status is the status for that kind, and its subsystems and groups are restricted
+// to those that participate in that kind. Group statuses are not recomputed. If k is
+// empty, this method returns this state unchanged. If no status was computed for k,
+// the copy's overall status is StatusBad, as with StatusOf, and it has no subsystems
+// or groups.
+func (ms MonitorState) ForKind(k Kind) MonitorState {
+	if len(k) == 0 {
+		return ms
+	}
+
+	view := ms
+	view.Subsystems = Subsystems{}
+	view.Groups = Groups{}
+
+	var known bool
+	if view.Status, known = ms.StatusOf(k); !known {
+		return view
+	}
+
+	participating := make(map[Name]bool)
+	for s := range ms.Subsystems.All() {
+		if s.HasKind(k) {
+			view.Subsystems.ss = append(view.Subsystems.ss, s)
+			participating[s.Group] = true
+		}
+	}
+
+	for g := range ms.Groups.All() {
+		if participating[g.Name] {
+			view.Groups.gs = append(view.Groups.gs, g)
+		}
+	}
+
+	return view
+}
+
+// HasKind tests if this subsystem participates in the given kind. A subsystem
+// with no kinds participates in every kind.
+func (s Subsystem) HasKind(k Kind) bool {
+	return len(s.Kinds) == 0 || slices.Contains(s.Kinds, k)
+}
+
+// unsafeKinds returns the kinds for which this Monitor computes a status, which
+// are the standard kinds along with any kind declared by a subsystem.
+//
+// This method must be executed under the monitor lock or in a situation where no
+// concurrent invocation is possible.
+func (m *Monitor) unsafeKinds() []Kind {
+	kinds := StandardKinds()
+	for _, st := range m.trackers {
+		for _, k := range st.definition.Kinds {
+			if !slices.Contains(kinds, k) {
+				kinds = append(kinds, k)
+			}
+		}
+	}
+
+	return kinds
+}
+
+// unsafeAggregateKinds computes the overall status for each kind.
+//
+// This method must be executed under the monitor lock or in a situation where no
+// concurrent invocation is possible.
+func (m *Monitor) unsafeAggregateKinds() KindStatuses {
+	kinds := KindStatuses{
+		ks: make(map[Kind]Status),
+	}
+
+	for _, k := range m.unsafeKinds() {
+		kinds.ks[k] = m.unsafeAggregateKind(k)
+	}
+
+	return kinds
+}
+
+// unsafeAggregateKind computes the overall status for the given kind from the
+// active subsystems that participate in that kind. Groups are aggregated using
+// only their participating members.
+//
+// This method must be executed under the monitor lock or in a situation where no
+// concurrent invocation is possible.
+func (m *Monitor) unsafeAggregateKind(k Kind) Status {
+	var (
+		active  []Subsystem
+		grouped map[Name][]Subsystem
+	)
+
+	for _, st := range m.trackers {
+		switch {
+		case !st.current.active() || !st.current.HasKind(k):
+			continue
+
+		case len(st.definition.Group) > 0:
+			if grouped == nil {
+				grouped = make(map[Name][]Subsystem)
+			}
+
+			grouped[st.definition.Group] = append(grouped[st.definition.Group], st.current)
+
+		default:
+			active = append(active, st.current)
+		}
+	}
+
+	for _, gt := range m.groups {
+		members := grouped[gt.definition.Name]
+		if len(members) == 0 {
+			continue
+		}
+
+		g := Group{
+			Name:        gt.definition.Name,
+			Status:      gt.definition.Aggregator.Aggregate(Subsystems{ss: members}),
+			NonCritical: gt.definition.NonCritical,
+			Metadata:    gt.definition.Metadata,
+		}
+
+		active = append(active, g.asSubsystem())
+	}
+
+	return m.aggregator.Aggregate(Subsystems{ss: active})
+}
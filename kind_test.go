@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type KindTestSuite struct {
+	suite.Suite
+}
+
+func (suite *KindTestSuite) testAsKindStatusesEmpty() {
+	ks := AsKindStatuses(nil)
+	suite.Zero(ks.Len())
+
+	var called bool
+	for range ks.All() {
+		called = true
+	}
+
+	suite.False(called)
+
+	status, ok := MonitorState{Kinds: ks}.StatusOf(KindLiveness)
+	suite.False(ok)
+	suite.Equal(StatusBad, status)
+}
+
+func (suite *KindTestSuite) testAsKindStatusesNotEmpty() {
+	original := map[Kind]Status{
+		KindStartup:   StatusWarn,
+		KindLiveness:  StatusGood,
+		KindReadiness: StatusBad,
+	}
+
+	ks := AsKindStatuses(original)
+	original[KindLiveness] = StatusBad
+	suite.Equal(3, ks.Len())
+
+	var (
+		kinds    []Kind
+		statuses []Status
+	)
+
+	for k, s := range ks.All() {
+		kinds = append(kinds, k)
+		statuses = append(statuses, s)
+	}
+
+	suite.Equal([]Kind{KindLiveness, KindReadiness, KindStartup}, kinds)
+	suite.Equal([]Status{StatusGood, StatusBad, StatusWarn}, statuses)
+
+	var count int
+	for range ks.All() {
+		count++
+		break
+	}
+
+	suite.Equal(1, count, "All needs to honor early return")
+}
+
+func (suite *KindTestSuite) testAsKindStatusesMarshalJSON() {
+	original := map[Kind]Status{
+		KindLiveness:  StatusGood,
+		KindReadiness: StatusBad,
+	}
+
+	expected, err := json.Marshal(original)
+	suite.Require().NoError(err)
+
+	actual, err := AsKindStatuses(original).MarshalJSON()
+	suite.Require().NoError(err)
+	suite.JSONEq(string(expected), string(actual))
+}
+
+func (suite *KindTestSuite) TestAsKindStatuses() {
+	suite.Run("Empty", suite.testAsKindStatusesEmpty)
+	suite.Run("NotEmpty", suite.testAsKindStatusesNotEmpty)
+	suite.Run("MarshalJSON", suite.testAsKindStatusesMarshalJSON)
+}
+
+func TestKind(t *testing.T) {
+	suite.Run(t, new(KindTestSuite))
+}
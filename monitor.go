@@ -7,6 +7,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -20,6 +22,23 @@ var (
 	// ErrMonitorShutdown is returned by Monitor.Shutdown to indicate that the
 	// Monitor has not yet been started or has already been Shutdown.
 	ErrMonitorShutdown = errors.New("the monitor has been shutdown")
+
+	// ErrNoSuchSubsystem indicates that no subsystem with a given name is
+	// registered with a Monitor.
+	ErrNoSuchSubsystem = errors.New("no such subsystem")
+
+	// ErrSubsystemPaused is returned by Monitor.Pause to indicate that the
+	// subsystem has already been paused.
+	ErrSubsystemPaused = errors.New("the subsystem has been paused")
+
+	// ErrSubsystemNotPaused is returned by Monitor.Resume to indicate that the
+	// subsystem is not paused.
+	ErrSubsystemNotPaused = errors.New("the subsystem is not paused")
+
+	// ErrSubsystemStale indicates that a subsystem with a TTL did not receive
+	// an update in time. The errors recorded for stale subsystems will wrap
+	// this error.
+	ErrSubsystemStale = errors.New("the subsystem is stale")
 )
 
 // MonitorState holds a snapshot of the state of a Monitor.
@@ -34,8 +53,16 @@ type MonitorState struct {
 	LastUpdate time.Time `json:"lastUpdate" yaml:"lastUpdate"`
 
 	// Subsystems is a snapshot of the state of each subsystem within
-	// the Monitor.
+	// the Monitor. This includes subsystems that belong to groups.
 	Subsystems Subsystems `json:"subsystems" yaml:"subsystems"`
+
+	// Groups is a snapshot of the state of each group within the Monitor.
+	Groups Groups `json:"groups" yaml:"groups"`
+
+	// Kinds is the overall status of the Monitor for each kind, computed from
+	// only the subsystems that participate in that kind. This always includes
+	// the standard kinds. Use StatusOf to look up the status of a single kind.
+	Kinds KindStatuses `json:"kinds" yaml:"kinds"`
 }
 
 // subsystemTracker holds all the information for tracking the state of
@@ -50,65 +77,483 @@ type subsystemTracker struct {
 	// newTimer is the timer strategy "inherited" from the containing monitor
 	newTimer newTimer
 
+	// random is the jitter strategy "inherited" from the containing monitor
+	random random
+
 	// unsafeUpdateState is the "inherited" non-atomic closure that updates monitor
 	// state.
-	unsafeUpdateState func(time.Time)
+	unsafeUpdateState func(time.Time, Name)
 
 	// definition is the configuration used to create this subsystem
 	definition Definition
 
-	// current represents the current state of this monitor.  This is a pointer
-	// into an element of the Monitor's subsystems.
-	current *Subsystem
+	// current represents the current state of this subsystem.
+	current Subsystem
+
+	// removed indicates that this subsystem has been removed from its Monitor.
+	// Updates to a removed subsystem are ignored.
+	removed bool
+
+	// cancelTasks stops the currently running background tasks, if any.
+	cancelTasks context.CancelFunc
+
+	// tasksDone is closed when the currently running background tasks exit.
+	tasksDone chan struct{}
+
+	// tasks tracks the currently running background tasks, including any Probe
+	// invocations that have not returned. This is nil if no tasks are running.
+	tasks *sync.WaitGroup
+
+	// abandoned is closed when a Probe that was abandoned after exceeding its
+	// timeout finally returns. This is nil if no Probe has been abandoned.
+	abandoned chan struct{}
+
+	// heartbeat receives a signal for each update to this subsystem.  This is
+	// used to reset the TTL.
+	heartbeat chan struct{}
+
+	// history holds recent transitions of this subsystem. This will be nil
+	// if history is disabled.
+	history *history
+
+	// transitions are the timestamps of recent changes in observed status, used
+	// to detect flapping.
+	transitions []time.Time
+
+	// flapping receives a signal for each transition while this subsystem is
+	// flapping.  This is used to restart the quiet period.
+	flapping chan struct{}
+
+	// stats accumulates uptime and availability statistics for this subsystem.
+	stats statsTracker
+
+	// startupEnds is the time at which this subsystem's startup grace period ends.
+	// This is the zero time if the grace period has not begun.
+	startupEnds time.Time
+
+	// override is the manual override for this subsystem, if any.
+	override *Override
+
+	// windows are the maintenance windows for this subsystem that have not yet ended.
+	windows []MaintenanceWindow
+
+	// overrides receives a signal each time the override or maintenance windows change.
+	// This is used to reschedule the override task.
+	overrides chan struct{}
 }
 
 // initialize sets up this tracker's initial state, using both its definition
 // and information from the containing Monitor.
-func (sst *subsystemTracker) initialize(m *Monitor, initialLastUpdate time.Time, current *Subsystem) {
+func (sst *subsystemTracker) initialize(m *Monitor, initialLastUpdate time.Time) {
 	sst.now = m.now
 	sst.newTimer = m.newTimer
+	sst.random = m.random
 
 	// take the initial state from the definition
-	sst.current = current
 	sst.current.Name = sst.definition.Name
 	sst.current.Status = sst.definition.Status
+	sst.current.ObservedStatus = sst.definition.Status
 	sst.current.NonCritical = sst.definition.NonCritical
+	sst.current.Group = sst.definition.Group
+	sst.current.Kinds = slices.Clone(sst.definition.Kinds)
 	sst.current.Metadata = sst.definition.Metadata
 	sst.current.LastUpdate = initialLastUpdate
 
-	// normalize the probe interval
+	sst.initializeHistory(m.defaultHistorySize)
+	sst.initializeTTL()
+	sst.initializeStartup(m.defaultStartupGracePeriod)
+	sst.initializeFlapping()
+	sst.initializeOverrides()
+	sst.initializeProbe(m)
+}
+
+// initializeTTL applies defaults for this subsystem's TTL.
+func (sst *subsystemTracker) initializeTTL() {
+	sst.heartbeat = make(chan struct{}, 1)
+	if sst.definition.TTL > 0 && sst.definition.StaleStatus == StatusGood {
+		sst.definition.StaleStatus = StatusBad
+	}
+}
+
+// initializeProbe normalizes this subsystem's probe configuration, applying the
+// defaults from the containing Monitor.
+func (sst *subsystemTracker) initializeProbe(m *Monitor) {
 	if sst.definition.Probe == nil {
 		sst.definition.ProbeInterval = 0
-	} else if sst.definition.ProbeInterval <= 0 {
+		sst.definition.ProbeTimeout = 0
+		return
+	}
+
+	if sst.definition.ProbeInterval <= 0 {
 		sst.definition.ProbeInterval = m.defaultProbeInterval
 	}
+
+	if sst.definition.ProbeTimeout <= 0 {
+		sst.definition.ProbeTimeout = m.defaultProbeTimeout
+	}
+
+	if sst.definition.ProbeJitter <= 0 && sst.definition.ProbeJitterFactor <= 0 {
+		sst.definition.ProbeJitter = m.defaultProbeJitter
+		sst.definition.ProbeJitterFactor = m.defaultProbeJitterFactor
+	}
+
+	if sst.definition.ProbeStagger <= 0 {
+		sst.definition.ProbeStagger = m.defaultProbeStagger
+	}
+
+	sst.definition.ProbeImmediately = sst.definition.ProbeImmediately || m.probeImmediately
+}
+
+// hasTasks tests if this subsystem's configuration requires any background goroutines.
+func (sst *subsystemTracker) hasTasks() bool {
+	return sst.definition.Probe != nil ||
+		sst.definition.TTL > 0 ||
+		sst.definition.FlapThreshold > 0 ||
+		sst.definition.StartupGracePeriod > 0 ||
+		sst.override != nil ||
+		len(sst.windows) > 0
 }
 
-// startProbeTask ensures that a background goroutine is running
-// to monitor the results from a Probe. If this subsystem has no Probe,
-// this method does nothing.
+// startTasks ensures that this subsystem's background goroutines are running.
+// A subsystem with a Probe has a goroutine that monitors the results from that
+// Probe. A subsystem with a TTL has a goroutine that marks the subsystem as stale
+// when no updates arrive in time. A subsystem that detects flapping has a goroutine
+// that clears the flapping state after the quiet period, and a subsystem in its
+// startup grace period has a goroutine that ends that period. If this subsystem has
+// none of these and no overrides, is paused, or already has running tasks, this method
+// does nothing. Otherwise, a goroutine that applies overrides as they start and expire
+// is always started, since overrides may be added at any time.
 //
-// If this method starts a goroutine, it will stop with the supplied
-// context is canceled.
-func (sst *subsystemTracker) startProbeTask(ctx context.Context) {
-	if sst.definition.Probe == nil {
+// The first time this method starts tasks, it also begins the startup grace period.
+//
+// If this method starts any goroutines, they will stop when either the supplied
+// context is canceled or stopTasks is called.
+//
+// If the Probe is to be run immediately and initial is not nil, initial will
+// be marked done once that first Probe has returned.
+//
+// This method must be executed under the monitor lock.
+func (sst *subsystemTracker) startTasks(ctx context.Context, initial *sync.WaitGroup) {
+	if sst.current.Paused || sst.cancelTasks != nil || !sst.hasTasks() {
 		return
 	}
 
+	sst.unsafeBeginStartup()
+
+	tasks := new(sync.WaitGroup)
+	sst.tasks = tasks
+	ctx, sst.cancelTasks = context.WithCancel(ctx)
+	if sst.definition.Probe != nil {
+		immediate := sst.definition.ProbeImmediately
+		if !immediate {
+			initial = nil
+		} else if initial != nil {
+			initial.Add(1)
+		}
+
+		spawn(tasks, func() { sst.probeTask(ctx, immediate, initial, tasks) })
+	}
+
+	if sst.definition.TTL > 0 {
+		spawn(tasks, func() { sst.ttlTask(ctx) })
+	}
+
+	if sst.definition.FlapThreshold > 0 {
+		spawn(tasks, func() { sst.flapTask(ctx) })
+	}
+
+	spawn(tasks, func() { sst.overrideTask(ctx) })
+	if sst.current.Starting {
+		// a paused or restarted subsystem only waits out the rest of its grace period
+		remaining := sst.startupEnds.Sub(sst.now())
+		spawn(tasks, func() { sst.startupTask(ctx, remaining) })
+	}
+
+	done := make(chan struct{})
+	sst.tasksDone = done
 	go func() {
-		for {
-			timeCh, stop := sst.newTimer(sst.definition.ProbeInterval)
-			select {
-			case <-ctx.Done():
-				stop()
-				return
-
-			case <-timeCh:
-				s, err := sst.definition.Probe(ctx)
-				sst.Update(s, err)
-			}
+		tasks.Wait()
+		close(done)
+	}()
+}
+
+// spawn runs a task in its own goroutine, tracked by the given tasks.
+func spawn(tasks *sync.WaitGroup, task func()) {
+	tasks.Add(1)
+	go func() {
+		defer tasks.Done()
+		task()
+	}()
+}
+
+// probeTask invokes this subsystem's Probe on its interval until the given
+// context is canceled. Each Probe invocation is tracked by the given tasks.
+func (sst *subsystemTracker) probeTask(ctx context.Context, immediate bool, initial, tasks *sync.WaitGroup) {
+	// the results of the most recent probe, which drive the next interval
+	var (
+		last     = StatusGood
+		failures int
+	)
+
+	probe := func() {
+		if sst.definition.SuppressProbes && sst.blocked() {
+			return
+		}
+
+		last = sst.probe(ctx, tasks)
+		if last == StatusGood {
+			failures = 0
+		} else {
+			failures++
+		}
+	}
+
+	if immediate {
+		probe()
+		if initial != nil {
+			initial.Done()
+		}
+	}
+
+	delay := sst.nextProbeDelay(last, failures)
+	if !immediate && sst.definition.ProbeStagger > 0 {
+		delay += sst.random(sst.definition.ProbeStagger)
+	}
+
+	for {
+		timeCh, stop := sst.newTimer(delay)
+		select {
+		case <-ctx.Done():
+			stop()
+			return
+
+		case <-timeCh:
+			probe()
+		}
+
+		delay = sst.nextProbeDelay(last, failures)
+	}
+}
+
+// ttlTask marks this subsystem as stale whenever no update arrives within its
+// TTL. Once stale, the TTL is not restarted until the next update. This method
+// returns when the given context is canceled.
+func (sst *subsystemTracker) ttlTask(ctx context.Context) {
+	for {
+		timeCh, stop := sst.newTimer(sst.definition.TTL)
+		select {
+		case <-ctx.Done():
+			stop()
+			return
+
+		case <-sst.heartbeat:
+			stop()
+			continue
+
+		case <-timeCh:
+			sst.expire(ctx)
 		}
+
+		// wait for the subsystem to be updated before restarting the TTL
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-sst.heartbeat:
+		}
+	}
+}
+
+// expire transitions this subsystem to its stale status. Thresholds do not
+// apply to this transition. If the given context has been canceled, this
+// method does nothing.
+func (sst *subsystemTracker) expire(ctx context.Context) {
+	defer sst.lock.Unlock()
+	sst.lock.Lock()
+
+	if sst.removed || ctx.Err() != nil {
+		return
+	}
+
+	previous := sst.current.ObservedStatus
+	sst.current.ObservedStatus = sst.definition.StaleStatus
+	sst.current.Pending = nil
+	sst.current.LastError = AddStatus(
+		fmt.Errorf("%w: no update to subsystem [%s] within %s", ErrSubsystemStale, sst.definition.Name, sst.definition.TTL),
+		sst.definition.StaleStatus,
+	)
+
+	sst.current.LastUpdate = sst.now().UTC()
+	sst.unsafeTrackFlapping(previous)
+	sst.unsafeRecordHistory()
+	sst.unsafeUpdateState(sst.current.LastUpdate, sst.definition.Name)
+}
+
+// blocked tests if this subsystem is currently StatusBad because of a dependency.
+func (sst *subsystemTracker) blocked() bool {
+	defer sst.lock.Unlock()
+	sst.lock.Lock()
+	return sst.current.DependencyError != nil
+}
+
+// nextProbeDelay computes the amount of time to wait before the next Probe,
+// given the status of the last Probe and the number of consecutive Probes that
+// have not reported StatusGood. The returned delay includes any jitter.
+func (sst *subsystemTracker) nextProbeDelay(last Status, failures int) time.Duration {
+	interval := sst.definition.ProbeInterval
+	switch {
+	case last == StatusWarn && sst.definition.WarnProbeInterval > 0:
+		interval = sst.definition.WarnProbeInterval
+
+	case last == StatusBad && sst.definition.BadProbeInterval > 0:
+		interval = sst.definition.BadProbeInterval
+	}
+
+	if failures > 1 && sst.definition.ProbeBackoff > 1 {
+		limit := time.Duration(math.MaxInt64)
+		if sst.definition.MaxProbeInterval > 0 {
+			limit = sst.definition.MaxProbeInterval
+		}
+
+		// compute in floating point to guard against overflow
+		backoff := float64(interval) * math.Pow(sst.definition.ProbeBackoff, float64(failures-1))
+		if backoff >= float64(limit) {
+			interval = limit
+		} else {
+			interval = time.Duration(backoff)
+		}
+	}
+
+	jitter := sst.definition.ProbeJitter
+	if jitter <= 0 {
+		jitter = time.Duration(float64(interval) * sst.definition.ProbeJitterFactor)
+	}
+
+	if jitter > 0 {
+		interval += sst.random(jitter)
+	}
+
+	return interval
+}
+
+// probe runs this subsystem's Probe once and updates this subsystem with the result.
+// The status reported by the Probe is returned. If tasks is not nil, it tracks the
+// Probe invocation until it returns.
+func (sst *subsystemTracker) probe(ctx context.Context, tasks *sync.WaitGroup) Status {
+	s, err := sst.runProbe(ctx, tasks)
+
+	// discard results from a probe that was paused or shutdown
+	// while it was running
+	if ctx.Err() == nil {
+		sst.Update(s, err)
+	}
+
+	return s
+}
+
+// runProbe invokes this subsystem's Probe, enforcing any configured timeout.
+//
+// If the Probe does not return before its timeout, this method returns immediately
+// with a timeout error. The abandoned Probe's eventual result is discarded. Until the
+// abandoned Probe returns, this method reports a timeout without invoking the Probe
+// again, so that a hung Probe does not accumulate goroutines.
+//
+// If tasks is not nil, it tracks the Probe invocation until it returns, even if the
+// Probe is abandoned. The caller must already be tracked by tasks.
+func (sst *subsystemTracker) runProbe(ctx context.Context, tasks *sync.WaitGroup) (Status, error) {
+	if sst.definition.ProbeTimeout <= 0 {
+		return sst.definition.Probe(ctx)
+	}
+
+	if sst.probeAbandoned() {
+		return StatusBad, AddStatus(
+			fmt.Errorf("%w: subsystem [%s] has not returned from a probe that exceeded %s", ErrProbeTimeout, sst.definition.Name, sst.definition.ProbeTimeout),
+			StatusBad,
+		)
+	}
+
+	type result struct {
+		s   Status
+		err error
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, sst.definition.ProbeTimeout)
+	defer cancel()
+
+	// buffered, so that an abandoned probe does not block forever
+	var (
+		results  = make(chan result, 1)
+		returned = make(chan struct{})
+	)
+
+	if tasks != nil {
+		tasks.Add(1)
+	}
+
+	go func() {
+		defer close(returned)
+		if tasks != nil {
+			defer tasks.Done()
+		}
+
+		s, err := sst.definition.Probe(probeCtx)
+		results <- result{s: s, err: err}
 	}()
+
+	select {
+	case r := <-results:
+		return r.s, r.err
+
+	case <-probeCtx.Done():
+		sst.abandon(returned)
+		return StatusBad, AddStatus(
+			fmt.Errorf("%w: subsystem [%s] did not respond within %s", ErrProbeTimeout, sst.definition.Name, sst.definition.ProbeTimeout),
+			StatusBad,
+		)
+	}
+}
+
+// abandon records a Probe that exceeded its timeout. The given channel must be
+// closed when that Probe returns.
+func (sst *subsystemTracker) abandon(returned chan struct{}) {
+	defer sst.lock.Unlock()
+	sst.lock.Lock()
+	sst.abandoned = returned
+}
+
+// probeAbandoned tests if a Probe that exceeded its timeout is still running.
+func (sst *subsystemTracker) probeAbandoned() bool {
+	defer sst.lock.Unlock()
+	sst.lock.Lock()
+
+	if sst.abandoned == nil {
+		return false
+	}
+
+	select {
+	case <-sst.abandoned:
+		sst.abandoned = nil
+		return false
+
+	default:
+		return true
+	}
+}
+
+// stopTasks stops any running background tasks. If no tasks are running,
+// this method does nothing and returns nil.
+//
+// The returned channel is closed once the stopped tasks' goroutines exit.
+//
+// This method must be executed under the monitor lock.
+func (sst *subsystemTracker) stopTasks() (done <-chan struct{}) {
+	if sst.cancelTasks != nil {
+		sst.cancelTasks()
+		done = sst.tasksDone
+		sst.cancelTasks, sst.tasksDone, sst.tasks = nil, nil, nil
+	}
+
+	return
 }
 
 // Update implements the Updater interface. This method updates this
@@ -118,12 +563,94 @@ func (sst *subsystemTracker) startProbeTask(ctx context.Context) {
 func (sst *subsystemTracker) Update(s Status, err error) {
 	defer sst.lock.Unlock()
 	sst.lock.Lock()
+	sst.unsafeUpdate(s, err)
+}
+
+// updateMonitor updates this tracker with the latest state of the child
+// Monitor it tracks.
+func (sst *subsystemTracker) updateMonitor(state MonitorState) {
+	defer sst.lock.Unlock()
+	sst.lock.Lock()
+
+	if !sst.removed {
+		sst.current.Monitor = &state
+	}
+
+	sst.unsafeUpdate(state.Status, nil)
+}
 
-	sst.current.Status = s
+// unsafeUpdate performs the work of Update.
+//
+// This method must be executed under the monitor lock.
+func (sst *subsystemTracker) unsafeUpdate(s Status, err error) {
+	if sst.removed {
+		return
+	}
+
+	// signal any TTL task without blocking.  if a signal is already
+	// pending, there's no need for another.
+	select {
+	case sst.heartbeat <- struct{}{}:
+	default:
+	}
+
+	previous := sst.current.ObservedStatus
+	sst.unsafeApplyThresholds(s)
+	sst.unsafeCheckStartup()
 	sst.current.LastError = err
 	sst.current.LastUpdate = sst.now().UTC()
+	sst.unsafeTrackFlapping(previous)
+	sst.unsafeRecordHistory()
+
+	sst.unsafeUpdateState(sst.current.LastUpdate, sst.definition.Name)
+}
+
+// unsafeComputeStatus computes the effective status of this subsystem as of the given
+// time. Starting from the observed status, this method applies flapping, the startup
+// grace period, dependencies, and finally any override, in that order. Every dependency
+// must already have had this method applied.
+//
+// This method must be executed under the monitor lock.
+func (sst *subsystemTracker) unsafeComputeStatus(t time.Time, byName map[Name]*subsystemTracker) {
+	sst.current.Status = sst.current.ObservedStatus
+	sst.unsafeApplyFlapping()
+	sst.unsafeApplyStartup()
+	sst.unsafeApplyDependencies(byName)
+	sst.unsafeApplyOverride(t)
+}
+
+// unsafeApplyThresholds updates this subsystem's observed status, taking into account the
+// failure and success thresholds. If the relevant threshold has not been reached,
+// the status change is recorded as pending.
+//
+// This method must be executed under the monitor lock.
+func (sst *subsystemTracker) unsafeApplyThresholds(s Status) {
+	if s == sst.current.ObservedStatus {
+		sst.current.Pending = nil
+		return
+	}
+
+	threshold := sst.definition.SuccessThreshold
+	if s > sst.current.ObservedStatus {
+		threshold = sst.definition.FailureThreshold
+	}
+
+	count := 1
+	if p := sst.current.Pending; p != nil && p.Status == s {
+		count = p.Count + 1
+	}
 
-	sst.unsafeUpdateState(sst.current.LastUpdate)
+	if count >= threshold {
+		sst.current.ObservedStatus = s
+		sst.current.Pending = nil
+	} else {
+		// always allocate a new Pending, as older snapshots may refer to the current one
+		sst.current.Pending = &Pending{
+			Status:    s,
+			Count:     count,
+			Threshold: threshold,
+		}
+	}
 }
 
 // Monitor is a health status monitor for application subsystems.
@@ -140,8 +667,22 @@ func (sst *subsystemTracker) Update(s Status, err error) {
 // that will be invoked on the configured interval. Each time a Probe returns
 // a result, that Probe's subsystem is update and the overall status of
 // the Monitor is recomputed.
+//
+// Any subsystem may be paused via Pause. A paused subsystem does not run its
+// Probe and does not contribute to the overall status of the Monitor until
+// it is resumed.
 type Monitor struct {
 	defaultProbeInterval time.Duration
+	defaultProbeTimeout  time.Duration
+	probeImmediately     bool
+	initialProbeWait     time.Duration
+
+	defaultProbeJitter       time.Duration
+	defaultProbeJitterFactor float64
+	defaultProbeStagger      time.Duration
+
+	defaultHistorySize        int
+	defaultStartupGracePeriod time.Duration
 
 	// now is the strategy used to get the current time.
 	// by default, time.Now is used.
@@ -153,67 +694,144 @@ type Monitor struct {
 	// Tests can replace this function to control probe monitoring.
 	newTimer newTimer
 
-	byName     map[Name]*subsystemTracker
-	trackers   []*subsystemTracker
-	subsystems []Subsystem
+	// random is the strategy used to compute jitter and stagger.
+	// if unset, defaultRandom is used.
+	random random
+
+	// aggregator computes the overall status from the subsystems.
+	// by default, CriticalAggregator is used.
+	aggregator Aggregator
 
-	// lock is primarily used to guard subsystem updates
+	byName   map[Name]*subsystemTracker
+	trackers []*subsystemTracker
+
+	// order is the set of trackers sorted so that each subsystem
+	// appears after the subsystems it depends on
+	order []*subsystemTracker
+
+	groupsByName map[Name]*groupTracker
+	groups       []*groupTracker
+
+	// lock is primarily used to guard subsystem updates as well as
+	// the set of subsystems
 	lock sync.Mutex
 
 	// state is the overall state of this Monitor
 	state atomic.Value
 
-	// cancel is the cancellation function used to control any probe tasks
+	// ctx is the root context for any background tasks.  This field is only
+	// set while the Monitor is running.
+	ctx context.Context
+
+	// cancel is the cancellation function used to control any background tasks
 	cancel context.CancelFunc
+
+	// parents are the subsystems in other Monitors that track this Monitor
+	parents parents
+
+	// subscribers receive events for each state update
+	subscribers subscribers
+
+	// changes wakes up anything waiting for the next state update
+	changes changes
 }
 
 // unsafeUpdateState performs the following:
 //
-// (1) computes the (possibly) new overall status based on the current subystem states
-// (2) updates the atomic state for this Monitor
+// (1) computes the effective status of each subsystem via unsafeComputeStatus, and records
+// it in each subsystem's statistics
+// (2) computes the status of each group based on the current states of its subsystems
+// (3) computes the (possibly) new overall status based on the current states of ungrouped
+// subsystems and groups, using this Monitor's Aggregator, both in total and for each kind
+// (4) updates the atomic state for this Monitor
+// (5) notifies any parent Monitors, subscribers, and waiters of the new state
 //
 // The timestamp of the update is supplied so that it's consistent with the timestamp
-// of any individual subsystem updates.
+// of any individual subsystem updates. The trigger is the name of the subsystem that
+// caused the update, which is empty if the update was not caused by a single subsystem.
 //
 // This method must be executed under the monitor lock or in a situation where no
 // concurrent invocation is possible.
-func (m *Monitor) unsafeUpdateState(timestamp time.Time) {
-	var (
-		overall           Status
-		criticalStatus    Status
-		nonCriticalStatus Status
-	)
+func (m *Monitor) unsafeUpdateState(timestamp time.Time, trigger Name) {
+	// statistics begin once this Monitor is running
+	running := m.ctx != nil
+	for _, st := range m.order {
+		st.unsafeComputeStatus(timestamp, m.byName)
+		st.stats.observe(timestamp, st.current.Status, running)
+	}
 
-	for _, st := range m.trackers {
-		switch {
-		case st.definition.NonCritical && st.current.Status > nonCriticalStatus:
-			nonCriticalStatus = st.current.Status
+	subsystems, active := m.unsafeCollectSubsystems()
+	groups, active := m.unsafeAggregateGroups(active)
+	m.unsafePublish(
+		MonitorState{
+			Status:     m.aggregator.Aggregate(Subsystems{ss: active}),
+			LastUpdate: timestamp,
+			Subsystems: Subsystems{ss: subsystems},
+			Groups:     Groups{gs: groups},
+			Kinds:      m.unsafeAggregateKinds(),
+		},
+		trigger,
+	)
+}
 
-		case !st.definition.NonCritical && st.current.Status > criticalStatus:
-			criticalStatus = st.current.Status
-		}
+// unsafeCollectSubsystems snapshots every subsystem, in the order they were defined.
+// Grouped subsystems are also collected into their group's scratch space. The returned
+// active subsystems are the ungrouped subsystems that affect the overall status.
+//
+// This method must be executed under the monitor lock or in a situation where no
+// concurrent invocation is possible.
+func (m *Monitor) unsafeCollectSubsystems() (subsystems, active []Subsystem) {
+	// NOTE: leave subsystems nil when there are no trackers, consistent with AsSubsystems
+	if len(m.trackers) > 0 {
+		subsystems = make([]Subsystem, len(m.trackers))
 	}
 
-	switch {
-	case criticalStatus != StatusGood:
-		overall = criticalStatus
+	for i, st := range m.trackers {
+		subsystems[i] = st.current
 
-	case nonCriticalStatus != StatusGood:
-		overall = StatusWarn
+		var gt *groupTracker
+		if len(st.definition.Group) > 0 {
+			gt = m.groupsByName[st.definition.Group]
+			gt.subsystems = append(gt.subsystems, st.current)
+		}
 
-	default:
-		overall = StatusGood
+		// paused or excluded subsystems do not affect the overall status
+		switch {
+		case !st.current.active():
+		case gt != nil:
+			gt.active = append(gt.active, st.current)
+
+		default:
+			active = append(active, st.current)
+		}
 	}
 
-	m.state.Store(MonitorState{
-		Status:     overall,
-		LastUpdate: timestamp,
-		Subsystems: AsSubsystems(m.subsystems...),
+	return
+}
+
+// unsafePublish stores the given state and notifies any parent Monitors, subscribers,
+// and waiters of it.
+//
+// This method must be executed under the monitor lock or in a situation where no
+// concurrent invocation is possible.
+func (m *Monitor) unsafePublish(state MonitorState, trigger Name) {
+	previous, _ := m.state.Load().(MonitorState)
+	m.state.Store(state)
+	m.notifyParents(state)
+	m.subscribers.publish(Event{
+		Previous: previous.Status,
+		Current:  state.Status,
+		Trigger:  trigger,
+		State:    state,
 	})
+
+	m.changes.broadcast()
 }
 
 // Len returns the count of subsystems that are defined for this Monitor.
 func (m *Monitor) Len() int {
+	defer m.lock.Unlock()
+	m.lock.Lock()
 	return len(m.trackers)
 }
 
@@ -222,17 +840,275 @@ func (m *Monitor) Len() int {
 //
 // This method always returns the same Updater instance for a given subsystem.
 // The returned Updater may be used at any time, including when the Monitor
-// has not been started or has been shutdown.
+// has not been started or has been shutdown. If the subsystem is removed,
+// its Updater will ignore any further updates.
 func (m *Monitor) Get(n Name) (Updater, error) {
-	// no locking necessary, as the set of subsystems is immutable
-	updater := m.byName[n]
-	if updater == nil {
-		return nil, fmt.Errorf("no subsystem with the name [%s] is registered", n)
+	defer m.lock.Unlock()
+	m.lock.Lock()
+
+	updater, err := m.find(n)
+	if err != nil {
+		return nil, err
 	}
 
 	return updater, nil
 }
 
+// Subsystem returns the current snapshot of the given subsystem. This snapshot
+// reflects the most recent update, and so may be newer than the same subsystem
+// in the value returned by State.
+//
+// If no such subsystem exists, this method returns an error that wraps ErrNoSuchSubsystem.
+func (m *Monitor) Subsystem(n Name) (Subsystem, error) {
+	defer m.lock.Unlock()
+	m.lock.Lock()
+
+	sst, err := m.find(n)
+	if err != nil {
+		return Subsystem{}, err
+	}
+
+	return sst.current, nil
+}
+
+// ProbeNow immediately runs the Probe for the given subsystem, outside of its usual
+// schedule, and updates the subsystem with the result. This method may be used whether
+// or not this Monitor is running. The subsystem's snapshot after the update is returned.
+//
+// If the given context is canceled before the Probe returns, the result is discarded
+// and the context's error is returned. While this Monitor is running, Stop waits for
+// the Probe to return just as it does for scheduled Probes.
+//
+// If no such subsystem exists, this method returns an error that wraps ErrNoSuchSubsystem.
+// If the subsystem has no Probe, ErrNoProbe is returned. If the subsystem is paused,
+// ErrSubsystemPaused is returned.
+func (m *Monitor) ProbeNow(ctx context.Context, n Name) (Subsystem, error) {
+	m.lock.Lock()
+	sst, err := m.find(n)
+	switch {
+	case err != nil:
+	case sst.definition.Probe == nil:
+		err = ErrNoProbe
+
+	case sst.current.Paused:
+		err = ErrSubsystemPaused
+	}
+
+	// while tasks are running, they cannot exit, so it is safe to add to them
+	var tasks *sync.WaitGroup
+	if err == nil && sst.tasks != nil {
+		tasks = sst.tasks
+		tasks.Add(1)
+	}
+
+	m.lock.Unlock()
+	if err != nil {
+		return Subsystem{}, err
+	}
+
+	// the probe must run outside the lock, as it may take some time
+	if tasks != nil {
+		defer tasks.Done()
+	}
+
+	sst.probe(ctx, tasks)
+	if err := ctx.Err(); err != nil {
+		return Subsystem{}, err
+	}
+
+	return m.Subsystem(n)
+}
+
+// find locates the tracker for the given subsystem.
+//
+// This method must be executed under the monitor lock or in a situation where no
+// concurrent invocation is possible.
+func (m *Monitor) find(n Name) (*subsystemTracker, error) {
+	sst := m.byName[n]
+	if sst == nil {
+		return nil, fmt.Errorf("%w: no subsystem with the name [%s] is registered", ErrNoSuchSubsystem, n)
+	}
+
+	return sst, nil
+}
+
+// Add registers a new subsystem with this Monitor. If this Monitor is running,
+// the subsystem's Probe, if any, is started immediately. The overall status of
+// this Monitor is recomputed to include the new subsystem.
+//
+// Updaters previously obtained for other subsystems are unaffected.
+func (m *Monitor) Add(d Definition) error {
+	defer m.lock.Unlock()
+	m.lock.Lock()
+
+	if err := m.validate(d); err != nil {
+		return err
+	}
+
+	sst, err := m.unsafeAdd(d)
+	if err != nil {
+		return err
+	}
+
+	order, err := sortDependencies(m.byName, m.trackers)
+	if err != nil {
+		m.unsafeRemove(sst)
+		return err
+	}
+
+	now := m.now().UTC()
+	sst.initialize(m, now)
+	if err := m.unsafeAttach(sst); err != nil {
+		m.unsafeRemove(sst)
+		return err
+	}
+
+	m.order = order
+	if m.ctx != nil {
+		sst.startTasks(m.ctx, nil)
+	}
+
+	m.unsafeUpdateState(now, d.Name)
+	return nil
+}
+
+// validate checks a subsystem definition against the groups defined for this Monitor.
+//
+// This method must be executed under the monitor lock or in a situation where no
+// concurrent invocation is possible.
+func (m *Monitor) validate(d Definition) error {
+	switch {
+	case m.groupsByName[d.Name] != nil:
+		return fmt.Errorf("the subsystem name [%s] conflicts with a group", d.Name)
+
+	case len(d.Group) > 0 && m.groupsByName[d.Group] == nil:
+		return fmt.Errorf("no group with the name [%s] exists for subsystem [%s]", d.Group, d.Name)
+
+	case d.Monitor != nil && d.Probe != nil:
+		return fmt.Errorf("the subsystem [%s] cannot have both a Probe and a Monitor", d.Name)
+
+	default:
+		return validateMaintenance(d.Name, d.Maintenance...)
+	}
+}
+
+// unsafeAdd creates a tracker for the given definition and registers it with this
+// Monitor. The returned tracker is not initialized.
+//
+// This method must be executed under the monitor lock or in a situation where no
+// concurrent invocation is possible.
+func (m *Monitor) unsafeAdd(d Definition) (*subsystemTracker, error) {
+	if m.byName[d.Name] != nil {
+		return nil, fmt.Errorf("a subsystem with the name [%s] already exists", d.Name)
+	}
+
+	sst := &subsystemTracker{
+		lock:              &m.lock,
+		unsafeUpdateState: m.unsafeUpdateState,
+		definition:        d,
+	}
+
+	m.byName[d.Name] = sst
+	m.trackers = append(m.trackers, sst)
+	return sst, nil
+}
+
+// Remove deregisters a subsystem from this Monitor. Any running Probe for the
+// subsystem is stopped, and the overall status of this Monitor is recomputed
+// without it. A subsystem cannot be removed while other subsystems depend on it.
+//
+// The removed subsystem's Updater will ignore any subsequent updates. Updaters
+// for other subsystems are unaffected.
+func (m *Monitor) Remove(n Name) error {
+	defer m.lock.Unlock()
+	m.lock.Lock()
+
+	sst, err := m.find(n)
+	if err != nil {
+		return err
+	}
+
+	for _, st := range m.trackers {
+		if slices.Contains(st.definition.DependsOn, n) {
+			return fmt.Errorf("subsystem [%s] cannot be removed, as [%s] depends on it", n, st.definition.Name)
+		}
+	}
+
+	m.unsafeRemove(sst)
+	m.unsafeUpdateState(m.now().UTC(), n)
+	return nil
+}
+
+// unsafeRemove stops and deregisters the given tracker.
+//
+// This method must be executed under the monitor lock or in a situation where no
+// concurrent invocation is possible.
+func (m *Monitor) unsafeRemove(sst *subsystemTracker) {
+	sst.stopTasks()
+	sst.removed = true
+	m.unsafeDetach(sst)
+	delete(m.byName, sst.definition.Name)
+	isTracker := func(t *subsystemTracker) bool {
+		return t == sst
+	}
+
+	m.trackers = slices.DeleteFunc(m.trackers, isTracker)
+	m.order = slices.DeleteFunc(m.order, isTracker)
+}
+
+// Pause stops monitoring the given subsystem. A paused subsystem's Probe, if any,
+// is not run, its TTL is not enforced, and the subsystem does not contribute to the
+// overall status of this Monitor. The subsystem's Updater may still be used while it
+// is paused, and the last update is retained in its snapshot.
+//
+// If the subsystem is already paused, this method does nothing and returns
+// ErrSubsystemPaused.
+func (m *Monitor) Pause(n Name) error {
+	defer m.lock.Unlock()
+	m.lock.Lock()
+
+	sst, err := m.find(n)
+	switch {
+	case err != nil:
+		return err
+
+	case sst.current.Paused:
+		return ErrSubsystemPaused
+	}
+
+	sst.current.Paused = true
+	sst.stopTasks()
+	m.unsafeUpdateState(m.now().UTC(), n)
+	return nil
+}
+
+// Resume restarts monitoring for a subsystem that was previously paused. If
+// this Monitor is running, the subsystem's Probe, if any, is restarted.
+//
+// If the subsystem is not paused, this method does nothing and returns
+// ErrSubsystemNotPaused.
+func (m *Monitor) Resume(n Name) error {
+	defer m.lock.Unlock()
+	m.lock.Lock()
+
+	sst, err := m.find(n)
+	switch {
+	case err != nil:
+		return err
+
+	case !sst.current.Paused:
+		return ErrSubsystemNotPaused
+	}
+
+	sst.current.Paused = false
+	if m.ctx != nil {
+		sst.startTasks(m.ctx, nil)
+	}
+
+	m.unsafeUpdateState(m.now().UTC(), n)
+	return nil
+}
+
 // State returns the last computed state for this Monitor.
 func (m *Monitor) State() MonitorState {
 	return m.state.Load().(MonitorState)
@@ -247,41 +1123,139 @@ func (m *Monitor) State() MonitorState {
 //
 // Start will update the overall timestamp for the State, but will not modify any
 // LastUpdate fields for subsystems.
+//
+// If this Monitor was configured with an initial probe wait, this method blocks
+// until every subsystem that probes immediately has received its first Probe result
+// or until the wait elapses, whichever comes first. In either case, the Monitor is
+// running when this method returns.
 func (m *Monitor) Start() error {
+	initial, err := m.start()
+	if err != nil || m.initialProbeWait <= 0 {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		initial.Wait()
+		close(done)
+	}()
+
+	timeCh, stop := m.newTimer(m.initialProbeWait)
+	defer stop()
+	select {
+	case <-done:
+	case <-timeCh:
+	}
+
+	return nil
+}
+
+// start performs the work of Start under the monitor lock. The returned
+// WaitGroup tracks any probes that were run immediately.
+func (m *Monitor) start() (*sync.WaitGroup, error) {
 	defer m.lock.Unlock()
 	m.lock.Lock()
 
 	if m.cancel != nil {
-		return ErrMonitorStarted
+		return nil, ErrMonitorStarted
 	}
 
-	m.unsafeUpdateState(m.now().UTC())
-	var rootCtx context.Context
-	rootCtx, m.cancel = context.WithCancel(context.Background())
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	initial := new(sync.WaitGroup)
 	for _, st := range m.trackers {
-		st.startProbeTask(rootCtx)
+		st.startTasks(m.ctx, initial)
 	}
 
-	return nil
+	// update the state after starting tasks, as that may begin startup grace periods
+	m.unsafeUpdateState(m.now().UTC(), "")
+	return initial, nil
 }
 
 // Shutdown stops any running tasks. The status of subsystems are preserved.
 // After this method has been called, Probes are no longer run but any
 // Updaters may still be used to update subsystem states.
 //
+// This method does not wait for any in-flight Probes. Use Stop to wait
+// for background tasks to exit.
+//
 // This method is idempotent. If this Monitor is not running,
 // this method does nothing and returns ErrMonitorShutdown.
 func (m *Monitor) Shutdown() error {
+	_, err := m.shutdown()
+	return err
+}
+
+// Stop is like Shutdown, but waits for any running background tasks, such as probes,
+// to exit. Once this method returns nil, no Probe results or TTL expirations will update
+// this Monitor until it is started again.
+//
+// If the context is canceled before all tasks have exited, this method returns
+// a *StopError that identifies the subsystems whose tasks were still running. The
+// Monitor is shutdown in either case.
+//
+// This method is idempotent. If this Monitor is not running,
+// this method does nothing and returns ErrMonitorShutdown.
+func (m *Monitor) Stop(ctx context.Context) error {
+	tasks, err := m.shutdown()
+	if err != nil {
+		return err
+	}
+
+	var pending []Name
+	for _, t := range tasks {
+		select {
+		case <-t.done:
+		case <-ctx.Done():
+		}
+
+		// check each task after the context is canceled, so that every
+		// task that hasn't exited is reported
+		select {
+		case <-t.done:
+		default:
+			pending = append(pending, t.name)
+		}
+	}
+
+	if len(pending) > 0 {
+		return &StopError{
+			Subsystems: pending,
+			Err:        ctx.Err(),
+		}
+	}
+
+	return nil
+}
+
+// stoppedTask describes a subsystem's background tasks that were stopped during shutdown.
+type stoppedTask struct {
+	name Name
+	done <-chan struct{}
+}
+
+// shutdown performs the work of Shutdown under the monitor lock. The returned
+// slice describes each subsystem whose background tasks were stopped.
+func (m *Monitor) shutdown() (tasks []stoppedTask, err error) {
 	defer m.lock.Unlock()
 	m.lock.Lock()
 
 	if m.cancel == nil {
-		return ErrMonitorShutdown
+		err = ErrMonitorShutdown
+		return
+	}
+
+	for _, st := range m.trackers {
+		if done := st.stopTasks(); done != nil {
+			tasks = append(tasks, stoppedTask{
+				name: st.definition.Name,
+				done: done,
+			})
+		}
 	}
 
 	m.cancel()
-	m.cancel = nil
-	return nil
+	m.ctx, m.cancel = nil, nil
+	return
 }
 
 // MonitorOption is a configurable option for tailoring a Monitor.
@@ -307,22 +1281,82 @@ func WithDefaultProbeInterval(i time.Duration) MonitorOption {
 	})
 }
 
+// WithDefaultProbeTimeout sets the default timeout for each invocation of any
+// registered probes for this Monitor. If unset or nonpositive, probes have no
+// timeout by default.
+func WithDefaultProbeTimeout(t time.Duration) MonitorOption {
+	return monitorOptionFunc(func(m *Monitor) error {
+		m.defaultProbeTimeout = max(t, 0)
+		return nil
+	})
+}
+
+// WithDefaultProbeJitter sets the default maximum jitter added to each probe interval
+// for subsystems that do not define their own jitter. If unset or zero, no absolute
+// jitter is applied by default. A negative jitter is an error.
+func WithDefaultProbeJitter(j time.Duration) MonitorOption {
+	return monitorOptionFunc(func(m *Monitor) error {
+		if j < 0 {
+			return fmt.Errorf("invalid probe jitter: %s", j)
+		}
+
+		m.defaultProbeJitter = j
+		return nil
+	})
+}
+
+// WithDefaultProbeJitterFactor sets the default maximum jitter, as a fraction of each
+// probe interval, for subsystems that do not define their own jitter. A default absolute
+// jitter set via WithDefaultProbeJitter takes precedence over this option. A negative
+// jitter factor is an error.
+func WithDefaultProbeJitterFactor(f float64) MonitorOption {
+	return monitorOptionFunc(func(m *Monitor) error {
+		if f < 0 {
+			return fmt.Errorf("invalid probe jitter factor: %f", f)
+		}
+
+		m.defaultProbeJitterFactor = f
+		return nil
+	})
+}
+
+// WithDefaultProbeStagger sets the default maximum random delay added before the
+// first probe for subsystems that do not define their own stagger. If unset or
+// nonpositive, first probes are not staggered by default.
+func WithDefaultProbeStagger(s time.Duration) MonitorOption {
+	return monitorOptionFunc(func(m *Monitor) error {
+		m.defaultProbeStagger = max(s, 0)
+		return nil
+	})
+}
+
+// WithImmediateProbes controls whether every registered probe for this Monitor
+// is invoked as soon as its probe task starts. When false, which is the default,
+// each subsystem's Definition.ProbeImmediately determines this behavior.
+func WithImmediateProbes(immediate bool) MonitorOption {
+	return monitorOptionFunc(func(m *Monitor) error {
+		m.probeImmediately = immediate
+		return nil
+	})
+}
+
+// WithInitialProbeWait sets the maximum amount of time that Monitor.Start will
+// block waiting on the first results of any probes that run immediately. If
+// unset or nonpositive, Start does not wait for any probes.
+func WithInitialProbeWait(d time.Duration) MonitorOption {
+	return monitorOptionFunc(func(m *Monitor) error {
+		m.initialProbeWait = max(d, 0)
+		return nil
+	})
+}
+
 // WithSubsystems defines several subsystems for the monitor.
 func WithSubsystems(defs ...Definition) MonitorOption {
 	return monitorOptionFunc(func(m *Monitor) error {
 		for _, d := range defs {
-			if m.byName[d.Name] != nil {
-				return fmt.Errorf("a subsystem with the name [%s] already exists", d.Name)
+			if _, err := m.unsafeAdd(d); err != nil {
+				return err
 			}
-
-			st := &subsystemTracker{
-				lock:              &m.lock,
-				unsafeUpdateState: m.unsafeUpdateState,
-				definition:        d,
-			}
-
-			m.byName[d.Name] = st
-			m.trackers = append(m.trackers, st)
 		}
 
 		return nil
@@ -333,7 +1367,7 @@ func WithSubsystems(defs ...Definition) MonitorOption {
 // set of options. The returned Monitor will not be running and
 // must be started in order to receive Probe updates.
 //
-// The set of subsystems is fixed and immutable after construction.
+// Subsystems may be added or removed after construction via Add and Remove.
 // The initial value returned by the Monitor from the State method will
 // be computed from the initial states of the subsystems.
 // If no subsystems are configured in the options, the returned
@@ -341,9 +1375,13 @@ func WithSubsystems(defs ...Definition) MonitorOption {
 func NewMonitor(opts ...MonitorOption) (*Monitor, error) {
 	m := &Monitor{
 		byName:               make(map[Name]*subsystemTracker),
+		groupsByName:         make(map[Name]*groupTracker),
 		defaultProbeInterval: DefaultProbeInterval,
+		defaultHistorySize:   DefaultHistorySize,
 		now:                  time.Now,
 		newTimer:             defaultNewTimer,
+		random:               defaultRandom,
+		aggregator:           CriticalAggregator(),
 	}
 
 	for _, o := range opts {
@@ -352,16 +1390,34 @@ func NewMonitor(opts ...MonitorOption) (*Monitor, error) {
 		}
 	}
 
-	m.subsystems = make([]Subsystem, len(m.trackers))
-
 	// now that the options are applied, make a pass over the subsystems
 	initialLastUpdate := m.now().UTC()
-	for i, sst := range m.trackers {
+	for _, sst := range m.trackers {
+		if err := m.validate(sst.definition); err != nil {
+			return nil, err
+		}
+
 		// pass the initialLastUpdate so all subsystem's get a consistent
 		// starting timestamp.
-		sst.initialize(m, initialLastUpdate, &m.subsystems[i])
+		sst.initialize(m, initialLastUpdate)
+	}
+
+	var err error
+	if m.order, err = sortDependencies(m.byName, m.trackers); err != nil {
+		return nil, err
+	}
+
+	// attach to any child monitors only after validation, so that a failed
+	// construction doesn't leave links behind.  this cannot fail, as a new
+	// Monitor cannot yet be nested within another.
+	//
+	// once attached, children may push updates concurrently, so the lock is required.
+	defer m.lock.Unlock()
+	m.lock.Lock()
+	for _, sst := range m.trackers {
+		_ = m.unsafeAttach(sst)
 	}
 
-	m.unsafeUpdateState(initialLastUpdate)
+	m.unsafeUpdateState(initialLastUpdate, "")
 	return m, nil
 }
@@ -4,6 +4,10 @@
 package haelu
 
 import (
+	"context"
+	"errors"
+	"maps"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -67,8 +71,11 @@ func (suite *MonitorTestSuite) names(defs ...Definition) (ns []Name) {
 func (suite *MonitorTestSuite) newExpectedSubsystem(d Definition) (s Subsystem) {
 	s.Name = d.Name
 	s.Status = d.Status
+	s.ObservedStatus = d.Status
 	s.Metadata = d.Metadata
 	s.NonCritical = d.NonCritical
+	s.Group = d.Group
+	s.Kinds = d.Kinds
 	s.LastUpdate = suite.startUTC()
 	return
 }
@@ -229,6 +236,2255 @@ func (suite *MonitorTestSuite) TestInitialStates() {
 	}
 }
 
+// newProbingMonitor is like newMonitor, but also returns a channel that receives the
+// duration of each timer created by the Monitor's background tasks.
+func (suite *MonitorTestSuite) newProbingMonitor(o ...MonitorOption) (*Monitor, <-chan time.Duration) {
+	timers := make(chan time.Duration, 100)
+	o = append(o,
+		monitorOptionFunc(func(m *Monitor) error {
+			m.now = suite.clock.Now
+			m.newTimer = notifyingFakeTimer(suite.clock, timers)
+			return nil
+		}),
+	)
+
+	m, err := NewMonitor(o...)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(m)
+	return m, timers
+}
+
+// receiveTimer waits for a background task to create a timer and returns its duration.
+func (suite *MonitorTestSuite) receiveTimer(timers <-chan time.Duration) time.Duration {
+	select {
+	case d := <-timers:
+		return d
+
+	case <-time.After(5 * time.Second):
+		suite.Require().Fail("no timer was created")
+		return 0
+	}
+}
+
+// assertNoTimer verifies that no background task creates a timer within a short window.
+func (suite *MonitorTestSuite) assertNoTimer(timers <-chan time.Duration) {
+	select {
+	case d := <-timers:
+		suite.Failf("unexpected timer", "a timer with duration %s was created", d)
+
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func (suite *MonitorTestSuite) testPauseResumeStatus() {
+	m := suite.newMonitor(
+		WithSubsystems(
+			Definition{Name: "good"},
+			Definition{Name: "bad", Status: StatusBad},
+		),
+	)
+
+	suite.Equal(StatusBad, m.State().Status)
+
+	suite.clock.Add(time.Second)
+	suite.NoError(m.Pause("bad"))
+	suite.ErrorIs(m.Pause("bad"), ErrSubsystemPaused)
+
+	expected := suite.newExpectedSubsystems(
+		Definition{Name: "good"},
+		Definition{Name: "bad", Status: StatusBad},
+	)
+
+	expected[1].Paused = true
+	suite.assertState(m, StatusGood, expected...)
+
+	// updates are still recorded while paused, but don't affect the overall status
+	suite.clock.Add(time.Second)
+	suite.assertUpdater(m, "bad").Update(StatusWarn, nil)
+	expected[1].Status = StatusWarn
+	expected[1].ObservedStatus = StatusWarn
+	expected[1].LastUpdate = suite.nowUTC()
+	suite.assertState(m, StatusGood, expected...)
+
+	suite.clock.Add(time.Second)
+	suite.NoError(m.Resume("bad"))
+	suite.ErrorIs(m.Resume("bad"), ErrSubsystemNotPaused)
+	expected[1].Paused = false
+	suite.assertState(m, StatusWarn, expected...)
+}
+
+func (suite *MonitorTestSuite) testPauseResumeNoSuchSubsystem() {
+	m := suite.newMonitor()
+	suite.ErrorIs(m.Pause("nosuch"), ErrNoSuchSubsystem)
+	suite.ErrorIs(m.Resume("nosuch"), ErrNoSuchSubsystem)
+
+	_, err := m.Get("nosuch")
+	suite.ErrorIs(err, ErrNoSuchSubsystem)
+}
+
+func (suite *MonitorTestSuite) testPauseResumeProbe() {
+	probed := make(chan struct{}, 1)
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{
+				Name:          "probed",
+				ProbeInterval: time.Minute,
+				Probe: func(context.Context) (Status, error) {
+					probed <- struct{}{}
+					return StatusWarn, nil
+				},
+			},
+		),
+	)
+
+	// pausing before Start means no probe task is started
+	suite.NoError(m.Pause("probed"))
+	suite.assertStart(m)
+	suite.assertNoTimer(timers)
+
+	suite.NoError(m.Resume("probed"))
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+
+	suite.clock.Add(time.Minute)
+	<-probed
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+	suite.Equal(StatusWarn, m.State().Status)
+
+	suite.NoError(m.Pause("probed"))
+	suite.assertNoTimer(timers)
+	suite.Equal(StatusGood, m.State().Status)
+
+	suite.assertShutdown(m)
+
+	// resuming a shutdown monitor does not restart the probe
+	suite.NoError(m.Resume("probed"))
+	suite.assertNoTimer(timers)
+}
+
+func (suite *MonitorTestSuite) TestPauseResume() {
+	suite.Run("Status", suite.testPauseResumeStatus)
+	suite.Run("NoSuchSubsystem", suite.testPauseResumeNoSuchSubsystem)
+	suite.Run("Probe", suite.testPauseResumeProbe)
+}
+
+func (suite *MonitorTestSuite) testAddRemoveStatus() {
+	m := suite.newMonitor(
+		WithSubsystems(
+			Definition{Name: "first"},
+		),
+	)
+
+	first := suite.assertUpdater(m, "first")
+	expected := suite.newExpectedSubsystems(Definition{Name: "first"})
+
+	suite.clock.Add(time.Second)
+	suite.NoError(m.Add(Definition{Name: "second", Status: StatusBad}))
+	suite.Error(m.Add(Definition{Name: "second"}))
+
+	second := suite.newExpectedSubsystem(Definition{Name: "second", Status: StatusBad})
+	second.LastUpdate = suite.nowUTC()
+	expected = append(expected, second)
+	suite.assertState(m, StatusBad, expected...)
+	suite.assertUpdater(m, "second")
+
+	suite.clock.Add(time.Second)
+	suite.NoError(m.Remove("second"))
+	suite.ErrorIs(m.Remove("second"), ErrNoSuchSubsystem)
+	suite.assertState(m, StatusGood, expected[0])
+
+	_, err := m.Get("second")
+	suite.ErrorIs(err, ErrNoSuchSubsystem)
+
+	// the Updater for the remaining subsystem is still valid
+	suite.clock.Add(time.Second)
+	first.Update(StatusWarn, nil)
+	expected[0].Status = StatusWarn
+	expected[0].ObservedStatus = StatusWarn
+	expected[0].LastUpdate = suite.nowUTC()
+	suite.assertState(m, StatusWarn, expected[0])
+}
+
+func (suite *MonitorTestSuite) testAddRemoveIgnoresRemovedUpdater() {
+	m := suite.newMonitor(
+		WithSubsystems(
+			Definition{Name: "removed"},
+		),
+	)
+
+	removed := suite.assertUpdater(m, "removed")
+	suite.NoError(m.Remove("removed"))
+	suite.assertState(m, StatusGood)
+
+	suite.clock.Add(time.Second)
+	removed.Update(StatusBad, nil)
+	suite.Equal(StatusGood, m.State().Status)
+	suite.Equal(suite.startUTC(), m.State().LastUpdate)
+}
+
+func (suite *MonitorTestSuite) testAddRemoveProbe() {
+	probed := make(chan struct{}, 1)
+	m, timers := suite.newProbingMonitor()
+	suite.assertStart(m)
+	suite.assertNoTimer(timers)
+
+	suite.NoError(m.Add(Definition{
+		Name:          "probed",
+		ProbeInterval: time.Minute,
+		Probe: func(context.Context) (Status, error) {
+			probed <- struct{}{}
+			return StatusBad, nil
+		},
+	}))
+
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+	suite.clock.Add(time.Minute)
+	<-probed
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+	suite.Equal(StatusBad, m.State().Status)
+
+	suite.NoError(m.Remove("probed"))
+	suite.clock.Add(time.Minute)
+	suite.assertNoTimer(timers)
+	suite.Equal(StatusGood, m.State().Status)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) TestAddRemove() {
+	suite.Run("Status", suite.testAddRemoveStatus)
+	suite.Run("IgnoresRemovedUpdater", suite.testAddRemoveIgnoresRemovedUpdater)
+	suite.Run("Probe", suite.testAddRemoveProbe)
+}
+
+func (suite *MonitorTestSuite) testProbeTimeoutExceeded(timeout time.Duration, o ...MonitorOption) {
+	release := make(chan struct{})
+	defer close(release)
+
+	d := Definition{
+		Name:          "hung",
+		ProbeInterval: time.Minute,
+		ProbeTimeout:  timeout,
+		Probe: func(ctx context.Context) (Status, error) {
+			_, hasDeadline := ctx.Deadline()
+			suite.True(hasDeadline)
+			<-release // ignore the context, as a misbehaving probe would
+			return StatusGood, nil
+		},
+	}
+
+	o = append(o, WithSubsystems(d))
+	m, timers := suite.newProbingMonitor(o...)
+	suite.assertStart(m)
+	suite.receiveTimer(timers)
+	suite.clock.Add(time.Minute)
+
+	// the probe loop must continue even though the probe never returned
+	suite.receiveTimer(timers)
+	state := m.State()
+	suite.Equal(StatusBad, state.Status)
+	suite.ErrorIs(state.Subsystems.Get(0).LastError, ErrProbeTimeout)
+	suite.Equal(StatusBad, ErrorStatus(state.Subsystems.Get(0).LastError))
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) testProbeTimeoutNotExceeded() {
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{
+				Name:          "fast",
+				ProbeInterval: time.Minute,
+				ProbeTimeout:  time.Hour,
+				Probe: func(ctx context.Context) (Status, error) {
+					return StatusWarn, nil
+				},
+			},
+		),
+	)
+
+	suite.assertStart(m)
+	suite.receiveTimer(timers)
+	suite.clock.Add(time.Minute)
+	suite.receiveTimer(timers)
+
+	state := m.State()
+	suite.Equal(StatusWarn, state.Status)
+	suite.NoError(state.Subsystems.Get(0).LastError)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) testProbeTimeoutHung() {
+	var (
+		calls   atomic.Int32
+		release = make(chan struct{})
+	)
+
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{
+				Name:          "hung",
+				ProbeInterval: time.Minute,
+				ProbeTimeout:  10 * time.Millisecond,
+				Probe: func(context.Context) (Status, error) {
+					if calls.Add(1) == 1 {
+						<-release // ignore the context, as a misbehaving probe would
+					}
+
+					return StatusGood, nil
+				},
+			},
+		),
+	)
+
+	suite.assertStart(m)
+	suite.receiveTimer(timers)
+	suite.clock.Add(time.Minute)
+	suite.receiveTimer(timers)
+
+	// the hung probe must not be invoked again until it returns
+	for range 3 {
+		suite.clock.Add(time.Minute)
+		suite.receiveTimer(timers)
+		suite.Equal(int32(1), calls.Load())
+		suite.Equal(StatusBad, m.State().Status)
+		suite.ErrorIs(m.State().Subsystems.Get(0).LastError, ErrProbeTimeout)
+	}
+
+	// once the hung probe returns, the next probe is invoked normally
+	close(release)
+	suite.Eventually(func() bool {
+		return !m.byName["hung"].probeAbandoned()
+	}, time.Second, time.Millisecond)
+
+	suite.clock.Add(time.Minute)
+	suite.receiveTimer(timers)
+	suite.Equal(int32(2), calls.Load())
+	suite.Equal(StatusGood, m.State().Status)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) TestProbeTimeout() {
+	suite.Run("Definition", func() {
+		// the definition's timeout must override the default
+		suite.testProbeTimeoutExceeded(10*time.Millisecond, WithDefaultProbeTimeout(time.Hour))
+	})
+
+	suite.Run("Default", func() {
+		suite.testProbeTimeoutExceeded(0, WithDefaultProbeTimeout(10*time.Millisecond))
+	})
+
+	suite.Run("NotExceeded", suite.testProbeTimeoutNotExceeded)
+	suite.Run("Hung", suite.testProbeTimeoutHung)
+}
+
+func (suite *MonitorTestSuite) testImmediateProbesNoWait(immediate bool, o ...MonitorOption) {
+	probed := make(chan struct{}, 1)
+	m, timers := suite.newProbingMonitor(append(o,
+		WithSubsystems(
+			Definition{
+				Name:             "immediate",
+				ProbeInterval:    time.Minute,
+				ProbeImmediately: immediate,
+				Probe: func(context.Context) (Status, error) {
+					probed <- struct{}{}
+					return StatusBad, nil
+				},
+			},
+		),
+	)...)
+
+	suite.assertStart(m)
+	<-probed
+
+	// the interval timer is only created after the immediate probe is done
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+	suite.Equal(StatusBad, m.State().Status)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) testImmediateProbesWait() {
+	m := suite.newMonitor(
+		WithInitialProbeWait(time.Hour),
+		WithSubsystems(
+			Definition{
+				Name:             "immediate",
+				ProbeInterval:    time.Minute,
+				ProbeImmediately: true,
+				Probe: func(context.Context) (Status, error) {
+					return StatusWarn, nil
+				},
+			},
+			Definition{
+				Name:          "delayed",
+				ProbeInterval: time.Minute,
+				Probe: func(context.Context) (Status, error) {
+					return StatusBad, nil
+				},
+			},
+		),
+	)
+
+	// Start must not return until the immediate probe has finished
+	suite.assertStart(m)
+	suite.Equal(StatusWarn, m.State().Status)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) testImmediateProbesWaitElapsed() {
+	release := make(chan struct{})
+	m, timers := suite.newProbingMonitor(
+		WithInitialProbeWait(time.Second),
+		WithImmediateProbes(true),
+		WithSubsystems(
+			Definition{
+				Name:          "slow",
+				ProbeInterval: time.Minute,
+				Probe: func(context.Context) (Status, error) {
+					<-release
+					return StatusBad, nil
+				},
+			},
+		),
+	)
+
+	started := make(chan error, 1)
+	go func() {
+		started <- m.Start()
+	}()
+
+	suite.Equal(time.Second, suite.receiveTimer(timers))
+	suite.clock.Add(time.Second)
+	suite.NoError(<-started)
+	suite.Equal(StatusGood, m.State().Status)
+
+	close(release)
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+	suite.Equal(StatusBad, m.State().Status)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) TestImmediateProbes() {
+	suite.Run("Monitor", func() {
+		suite.testImmediateProbesNoWait(false, WithImmediateProbes(true))
+	})
+
+	suite.Run("Definition", func() {
+		suite.testImmediateProbesNoWait(true)
+	})
+
+	suite.Run("Wait", suite.testImmediateProbesWait)
+	suite.Run("WaitElapsed", suite.testImmediateProbesWaitElapsed)
+}
+
+func (suite *MonitorTestSuite) testStopWaitsForProbes() {
+	var updated atomic.Bool
+	running := make(chan struct{})
+	release := make(chan struct{})
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{
+				Name:             "slow",
+				ProbeInterval:    time.Minute,
+				ProbeImmediately: true,
+				Probe: func(context.Context) (Status, error) {
+					close(running)
+					<-release
+					updated.Store(true)
+					return StatusBad, nil
+				},
+			},
+			Definition{
+				Name:          "fast",
+				ProbeInterval: time.Minute,
+				Probe: func(context.Context) (Status, error) {
+					return StatusGood, nil
+				},
+			},
+		),
+	)
+
+	suite.assertStart(m)
+	suite.receiveTimer(timers) // fast's timer
+	<-running
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := m.Stop(ctx)
+	var stopErr *StopError
+	suite.Require().ErrorAs(err, &stopErr)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Equal([]Name{"slow"}, stopErr.Subsystems)
+
+	suite.ErrorIs(m.Stop(context.Background()), ErrMonitorShutdown)
+	suite.ErrorIs(m.Shutdown(), ErrMonitorShutdown)
+
+	// the abandoned probe's result must be discarded
+	close(release)
+	suite.Eventually(updated.Load, time.Second, 10*time.Millisecond)
+	suite.Equal(StatusGood, m.State().Status)
+}
+
+func (suite *MonitorTestSuite) testStopComplete() {
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{
+				Name:          "probed",
+				ProbeInterval: time.Minute,
+				Probe: func(context.Context) (Status, error) {
+					return StatusBad, nil
+				},
+			},
+		),
+	)
+
+	suite.ErrorIs(m.Stop(context.Background()), ErrMonitorShutdown)
+	suite.assertStart(m)
+	suite.receiveTimer(timers)
+	suite.NoError(m.Stop(context.Background()))
+	suite.ErrorIs(m.Stop(context.Background()), ErrMonitorShutdown)
+
+	// the monitor can be restarted after being stopped
+	suite.assertStart(m)
+	suite.receiveTimer(timers)
+	suite.NoError(m.Stop(context.Background()))
+}
+
+func (suite *MonitorTestSuite) testStopWaitsForAbandonedProbes() {
+	var (
+		running = make(chan struct{})
+		release = make(chan struct{})
+	)
+
+	m := suite.newMonitor(
+		WithSubsystems(
+			Definition{
+				Name:             "hung",
+				ProbeInterval:    time.Minute,
+				ProbeTimeout:     time.Hour,
+				ProbeImmediately: true,
+				Probe: func(context.Context) (Status, error) {
+					close(running)
+					<-release // ignore the context, as a misbehaving probe would
+					return StatusBad, nil
+				},
+			},
+		),
+	)
+
+	suite.assertStart(m)
+	<-running
+
+	// stopping cancels the probe's context, but the probe itself has not returned
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := m.Stop(ctx)
+	var stopErr *StopError
+	suite.Require().ErrorAs(err, &stopErr)
+	suite.Equal([]Name{"hung"}, stopErr.Subsystems)
+	close(release)
+}
+
+func (suite *MonitorTestSuite) testStopWaitsForProbeNow() {
+	var (
+		running = make(chan struct{}, 1)
+		release = make(chan struct{})
+	)
+
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{
+				Name:          "slow",
+				ProbeInterval: time.Minute,
+				Probe: func(context.Context) (Status, error) {
+					running <- struct{}{}
+					<-release
+					return StatusBad, nil
+				},
+			},
+		),
+	)
+
+	suite.assertStart(m)
+	suite.receiveTimer(timers)
+
+	probed := make(chan error, 1)
+	go func() {
+		_, err := m.ProbeNow(context.Background(), "slow")
+		probed <- err
+	}()
+
+	<-running
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := m.Stop(ctx)
+	var stopErr *StopError
+	suite.Require().ErrorAs(err, &stopErr)
+	suite.Equal([]Name{"slow"}, stopErr.Subsystems)
+
+	close(release)
+	suite.NoError(<-probed)
+}
+
+func (suite *MonitorTestSuite) TestStop() {
+	suite.Run("WaitsForProbes", suite.testStopWaitsForProbes)
+	suite.Run("WaitsForAbandonedProbes", suite.testStopWaitsForAbandonedProbes)
+	suite.Run("WaitsForProbeNow", suite.testStopWaitsForProbeNow)
+	suite.Run("Complete", suite.testStopComplete)
+}
+
+// halfRandom is a deterministic random strategy that always produces half the
+// maximum duration.
+func halfRandom(d time.Duration) time.Duration {
+	return d / 2
+}
+
+func (suite *MonitorTestSuite) TestProbeJitter() {
+	probe := func(context.Context) (Status, error) {
+		return StatusGood, nil
+	}
+
+	testCases := []struct {
+		name       string
+		options    []MonitorOption
+		definition Definition
+		first      time.Duration
+		next       time.Duration
+	}{
+		{
+			name:       "None",
+			definition: Definition{ProbeInterval: time.Minute},
+			first:      time.Minute,
+			next:       time.Minute,
+		},
+		{
+			name:       "Jitter",
+			definition: Definition{ProbeInterval: time.Minute, ProbeJitter: 10 * time.Second},
+			first:      time.Minute + 5*time.Second,
+			next:       time.Minute + 5*time.Second,
+		},
+		{
+			name:       "JitterFactor",
+			definition: Definition{ProbeInterval: time.Minute, ProbeJitterFactor: 0.5},
+			first:      time.Minute + 15*time.Second,
+			next:       time.Minute + 15*time.Second,
+		},
+		{
+			name: "JitterPrecedence",
+			definition: Definition{
+				ProbeInterval:     time.Minute,
+				ProbeJitter:       10 * time.Second,
+				ProbeJitterFactor: 0.5,
+			},
+			first: time.Minute + 5*time.Second,
+			next:  time.Minute + 5*time.Second,
+		},
+		{
+			name:       "Stagger",
+			definition: Definition{ProbeInterval: time.Minute, ProbeStagger: 20 * time.Second},
+			first:      time.Minute + 10*time.Second,
+			next:       time.Minute,
+		},
+		{
+			name:       "StaggerIgnoredWhenImmediate",
+			definition: Definition{ProbeInterval: time.Minute, ProbeStagger: 20 * time.Second, ProbeImmediately: true},
+			first:      time.Minute,
+			next:       time.Minute,
+		},
+		{
+			name: "Defaults",
+			options: []MonitorOption{
+				WithDefaultProbeJitter(4 * time.Second),
+				WithDefaultProbeStagger(30 * time.Second),
+			},
+			definition: Definition{ProbeInterval: time.Minute},
+			first:      time.Minute + 17*time.Second,
+			next:       time.Minute + 2*time.Second,
+		},
+		{
+			name: "DefaultJitterFactor",
+			options: []MonitorOption{
+				WithDefaultProbeJitterFactor(0.1),
+			},
+			definition: Definition{ProbeInterval: time.Minute},
+			first:      time.Minute + 3*time.Second,
+			next:       time.Minute + 3*time.Second,
+		},
+		{
+			name: "DefinitionOverridesDefaults",
+			options: []MonitorOption{
+				WithDefaultProbeJitterFactor(0.1),
+				WithDefaultProbeStagger(30 * time.Second),
+			},
+			definition: Definition{
+				ProbeInterval: time.Minute,
+				ProbeJitter:   2 * time.Second,
+				ProbeStagger:  10 * time.Second,
+			},
+			first: time.Minute + 6*time.Second,
+			next:  time.Minute + time.Second,
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			d := testCase.definition
+			d.Name = "jittered"
+			d.Probe = probe
+
+			m, timers := suite.newProbingMonitor(append(testCase.options,
+				WithSubsystems(d),
+				monitorOptionFunc(func(m *Monitor) error {
+					m.random = halfRandom
+					return nil
+				}),
+			)...)
+
+			suite.assertStart(m)
+			suite.Equal(testCase.first, suite.receiveTimer(timers))
+			suite.clock.Add(testCase.first)
+			suite.Equal(testCase.next, suite.receiveTimer(timers))
+			suite.assertShutdown(m)
+		})
+	}
+
+	suite.Run("InvalidJitterFactor", func() {
+		m, err := NewMonitor(WithDefaultProbeJitterFactor(-1.0))
+		suite.Error(err)
+		suite.Nil(m)
+	})
+
+	suite.Run("InvalidJitter", func() {
+		m, err := NewMonitor(WithDefaultProbeJitter(-time.Second))
+		suite.Error(err)
+		suite.Nil(m)
+	})
+}
+
+func (suite *MonitorTestSuite) TestAdaptiveProbeInterval() {
+	testCases := []struct {
+		name       string
+		definition Definition
+		results    []Status
+
+		// expected has one more element than results, the first being the
+		// initial delay before any probe
+		expected []time.Duration
+	}{
+		{
+			name: "StatusIntervals",
+			definition: Definition{
+				ProbeInterval:     time.Minute,
+				WarnProbeInterval: 30 * time.Second,
+				BadProbeInterval:  10 * time.Second,
+			},
+			results: []Status{StatusBad, StatusBad, StatusWarn, StatusGood},
+			expected: []time.Duration{
+				time.Minute,
+				10 * time.Second,
+				10 * time.Second,
+				30 * time.Second,
+				time.Minute,
+			},
+		},
+		{
+			name: "Backoff",
+			definition: Definition{
+				ProbeInterval:    time.Minute,
+				BadProbeInterval: 10 * time.Second,
+				ProbeBackoff:     2.0,
+				MaxProbeInterval: 35 * time.Second,
+			},
+			results: []Status{StatusBad, StatusBad, StatusBad, StatusBad, StatusWarn, StatusGood, StatusBad},
+			expected: []time.Duration{
+				time.Minute,
+				10 * time.Second,
+				20 * time.Second,
+				35 * time.Second,
+				35 * time.Second,
+				35 * time.Second,
+				time.Minute,
+				10 * time.Second,
+			},
+		},
+		{
+			name: "UncappedBackoff",
+			definition: Definition{
+				ProbeInterval: time.Second,
+				ProbeBackoff:  3.0,
+			},
+			results: []Status{StatusWarn, StatusWarn, StatusWarn},
+			expected: []time.Duration{
+				time.Second,
+				time.Second,
+				3 * time.Second,
+				9 * time.Second,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			results := make(chan Status, len(testCase.results))
+			for _, r := range testCase.results {
+				results <- r
+			}
+
+			d := testCase.definition
+			d.Name = "adaptive"
+			d.Probe = func(ctx context.Context) (Status, error) {
+				select {
+				case r := <-results:
+					return r, nil
+
+				case <-ctx.Done():
+					return StatusGood, ctx.Err()
+				}
+			}
+
+			m, timers := suite.newProbingMonitor(WithSubsystems(d))
+			suite.assertStart(m)
+			for _, expected := range testCase.expected {
+				suite.Equal(expected, suite.receiveTimer(timers))
+				suite.clock.Add(expected)
+			}
+
+			suite.assertShutdown(m)
+		})
+	}
+}
+
+func (suite *MonitorTestSuite) TestThresholds() {
+	d := Definition{
+		Name:             "thresholds",
+		FailureThreshold: 3,
+		SuccessThreshold: 2,
+	}
+
+	m := suite.newMonitor(WithSubsystems(d))
+	u := suite.assertUpdater(m, d.Name)
+	expected := suite.newExpectedSubsystem(d)
+
+	steps := []struct {
+		update  Status
+		status  Status
+		pending *Pending
+	}{
+		{update: StatusBad, status: StatusGood, pending: &Pending{Status: StatusBad, Count: 1, Threshold: 3}},
+		{update: StatusBad, status: StatusGood, pending: &Pending{Status: StatusBad, Count: 2, Threshold: 3}},
+		{update: StatusWarn, status: StatusGood, pending: &Pending{Status: StatusWarn, Count: 1, Threshold: 3}},
+		{update: StatusBad, status: StatusGood, pending: &Pending{Status: StatusBad, Count: 1, Threshold: 3}},
+		{update: StatusBad, status: StatusGood, pending: &Pending{Status: StatusBad, Count: 2, Threshold: 3}},
+		{update: StatusBad, status: StatusBad, pending: nil},
+		{update: StatusGood, status: StatusBad, pending: &Pending{Status: StatusGood, Count: 1, Threshold: 2}},
+		{update: StatusBad, status: StatusBad, pending: nil},
+		{update: StatusWarn, status: StatusBad, pending: &Pending{Status: StatusWarn, Count: 1, Threshold: 2}},
+		{update: StatusWarn, status: StatusWarn, pending: nil},
+		{update: StatusGood, status: StatusWarn, pending: &Pending{Status: StatusGood, Count: 1, Threshold: 2}},
+		{update: StatusGood, status: StatusGood, pending: nil},
+	}
+
+	for i, step := range steps {
+		suite.clock.Add(time.Second)
+		u.Update(step.update, nil)
+
+		expected.Status = step.status
+		expected.ObservedStatus = step.status
+		expected.Pending = step.pending
+		expected.LastUpdate = suite.nowUTC()
+		suite.Equal(expected, m.State().Subsystems.Get(0), "step %d", i)
+		suite.Equal(step.status, m.State().Status, "step %d", i)
+	}
+}
+
+func (suite *MonitorTestSuite) testTTLExpires(staleStatus, expected Status) {
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{
+				Name:        "heartbeat",
+				TTL:         time.Minute,
+				StaleStatus: staleStatus,
+			},
+		),
+	)
+
+	u := suite.assertUpdater(m, "heartbeat")
+	suite.assertStart(m)
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+
+	// an update restarts the TTL
+	suite.clock.Add(30 * time.Second)
+	u.Update(StatusGood, nil)
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+
+	suite.clock.Add(time.Minute)
+	suite.Eventually(
+		func() bool { return m.State().Status == expected },
+		time.Second,
+		10*time.Millisecond,
+	)
+
+	sub := m.State().Subsystems.Get(0)
+	suite.Equal(expected, sub.Status)
+	suite.Equal(suite.nowUTC(), sub.LastUpdate)
+	suite.ErrorIs(sub.LastError, ErrSubsystemStale)
+	suite.Equal(expected, ErrorStatus(sub.LastError))
+
+	// the TTL is not restarted until the next update
+	suite.assertNoTimer(timers)
+	u.Update(StatusGood, nil)
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+	suite.Equal(StatusGood, m.State().Status)
+
+	suite.NoError(m.Stop(context.Background()))
+}
+
+func (suite *MonitorTestSuite) testTTLPaused() {
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{
+				Name: "heartbeat",
+				TTL:  time.Minute,
+			},
+		),
+	)
+
+	suite.assertStart(m)
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+	suite.NoError(m.Pause("heartbeat"))
+	suite.clock.Add(time.Minute)
+	suite.assertNoTimer(timers)
+	suite.Equal(StatusGood, m.State().Subsystems.Get(0).Status)
+
+	suite.NoError(m.Resume("heartbeat"))
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+	suite.NoError(m.Stop(context.Background()))
+}
+
+func (suite *MonitorTestSuite) TestTTL() {
+	suite.Run("DefaultStaleStatus", func() {
+		suite.testTTLExpires(StatusGood, StatusBad)
+	})
+
+	suite.Run("WarnStaleStatus", func() {
+		suite.testTTLExpires(StatusWarn, StatusWarn)
+	})
+
+	suite.Run("Paused", suite.testTTLPaused)
+}
+
+func (suite *MonitorTestSuite) TestWithAggregator() {
+	suite.Run("Custom", func() {
+		var seen []Name
+		m := suite.newMonitor(
+			WithAggregator(AggregatorFunc(func(ss Subsystems) Status {
+				seen = seen[:0]
+				for s := range ss.All() {
+					seen = append(seen, s.Name)
+				}
+
+				return StatusWarn
+			})),
+			WithSubsystems(
+				Definition{Name: "first"},
+				Definition{Name: "second"},
+			),
+		)
+
+		suite.Equal(StatusWarn, m.State().Status)
+		suite.Equal([]Name{"first", "second"}, seen)
+
+		// paused subsystems are never passed to the aggregator
+		suite.NoError(m.Pause("first"))
+		suite.Equal([]Name{"second"}, seen)
+	})
+
+	suite.Run("Nil", func() {
+		m := suite.newMonitor(
+			WithAggregator(nil),
+			WithSubsystems(
+				Definition{Name: "noncritical", Status: StatusBad, NonCritical: true},
+			),
+		)
+
+		suite.Equal(StatusWarn, m.State().Status)
+	})
+}
+
+func (suite *MonitorTestSuite) testGroupsStatus() {
+	defs := []Definition{
+		{Name: "db-primary", Group: "database"},
+		{Name: "db-replica-1", Group: "database"},
+		{Name: "db-replica-2", Group: "database"},
+		{Name: "cache-1", Group: "cache"},
+		{Name: "network"},
+	}
+
+	m := suite.newMonitor(
+		WithGroups(
+			GroupDefinition{Name: "database", Aggregator: QuorumAggregator(0.5)},
+			GroupDefinition{Name: "cache", NonCritical: true, Metadata: Values("tier", "memory")},
+		),
+		WithSubsystems(defs...),
+	)
+
+	expected := suite.newExpectedSubsystems(defs...)
+	suite.assertState(m, StatusGood, expected...)
+
+	state := m.State()
+	suite.Require().Equal(2, state.Groups.Len())
+	suite.Equal(
+		Group{
+			Name:       "database",
+			Status:     StatusGood,
+			LastUpdate: suite.startUTC(),
+			Subsystems: AsSubsystems(expected[0:3]...),
+		},
+		state.Groups.Get(0),
+	)
+
+	suite.Equal(
+		Group{
+			Name:        "cache",
+			Status:      StatusGood,
+			LastUpdate:  suite.startUTC(),
+			NonCritical: true,
+			Metadata:    Values("tier", "memory"),
+			Subsystems:  AsSubsystems(expected[3]),
+		},
+		state.Groups.Get(1),
+	)
+
+	// a minority of replicas failing only degrades the database group
+	suite.assertUpdater(m, "db-replica-1").Update(StatusBad, nil)
+	suite.Equal(StatusWarn, m.State().Groups.Get(0).Status)
+	suite.Equal(StatusWarn, m.State().Status)
+
+	suite.assertUpdater(m, "db-primary").Update(StatusBad, nil)
+	suite.Equal(StatusBad, m.State().Groups.Get(0).Status)
+	suite.Equal(StatusBad, m.State().Status)
+
+	// a noncritical group can only cause a warning
+	suite.assertUpdater(m, "db-primary").Update(StatusGood, nil)
+	suite.assertUpdater(m, "db-replica-1").Update(StatusGood, nil)
+	suite.assertUpdater(m, "cache-1").Update(StatusBad, nil)
+	suite.Equal(StatusBad, m.State().Groups.Get(1).Status)
+	suite.Equal(StatusWarn, m.State().Status)
+
+	// a group with no active subsystems does not affect the overall status
+	suite.NoError(m.Pause("cache-1"))
+	suite.Equal(StatusGood, m.State().Groups.Get(1).Status)
+	suite.Equal(1, m.State().Groups.Get(1).Subsystems.Len())
+	suite.Equal(StatusGood, m.State().Status)
+}
+
+func (suite *MonitorTestSuite) testGroupsInvalid() {
+	suite.Run("DuplicateGroup", func() {
+		m, err := NewMonitor(
+			WithGroups(GroupDefinition{Name: "group"}, GroupDefinition{Name: "group"}),
+		)
+
+		suite.Error(err)
+		suite.Nil(m)
+	})
+
+	suite.Run("NoSuchGroup", func() {
+		m, err := NewMonitor(
+			WithSubsystems(Definition{Name: "subsystem", Group: "nosuch"}),
+		)
+
+		suite.Error(err)
+		suite.Nil(m)
+	})
+
+	suite.Run("NameConflict", func() {
+		m, err := NewMonitor(
+			WithSubsystems(Definition{Name: "conflict"}),
+			WithGroups(GroupDefinition{Name: "conflict"}),
+		)
+
+		suite.Error(err)
+		suite.Nil(m)
+	})
+
+	suite.Run("Add", func() {
+		m := suite.newMonitor(WithGroups(GroupDefinition{Name: "group"}))
+		suite.Error(m.Add(Definition{Name: "subsystem", Group: "nosuch"}))
+		suite.Error(m.Add(Definition{Name: "group"}))
+		suite.NoError(m.Add(Definition{Name: "subsystem", Group: "group", Status: StatusWarn}))
+		suite.Equal(StatusWarn, m.State().Groups.Get(0).Status)
+		suite.Equal(StatusWarn, m.State().Status)
+	})
+}
+
+func (suite *MonitorTestSuite) TestGroups() {
+	suite.Run("Status", suite.testGroupsStatus)
+	suite.Run("Invalid", suite.testGroupsInvalid)
+}
+
+func (suite *MonitorTestSuite) testNestedStatus() {
+	grandchild := suite.newMonitor(WithSubsystems(Definition{Name: "leaf"}))
+	child := suite.newMonitor(
+		WithSubsystems(
+			Definition{Name: "grandchild", Monitor: grandchild},
+			Definition{Name: "local"},
+		),
+	)
+
+	parent := suite.newMonitor(
+		WithSubsystems(
+			Definition{Name: "child", Monitor: child, Status: StatusBad},
+		),
+	)
+
+	// the initial status comes from the child, not the definition
+	state := parent.State()
+	suite.Equal(StatusGood, state.Status)
+	suite.Require().NotNil(state.Subsystems.Get(0).Monitor)
+	suite.Equal(child.State(), *state.Subsystems.Get(0).Monitor)
+
+	suite.clock.Add(time.Second)
+	suite.assertUpdater(grandchild, "leaf").Update(StatusBad, nil)
+	suite.Equal(StatusBad, grandchild.State().Status)
+	suite.Equal(StatusBad, child.State().Status)
+
+	state = parent.State()
+	suite.Equal(StatusBad, state.Status)
+	suite.Equal(suite.nowUTC(), state.LastUpdate)
+
+	sub := state.Subsystems.Get(0)
+	suite.Equal(StatusBad, sub.Status)
+	suite.Equal(suite.nowUTC(), sub.LastUpdate)
+	suite.Require().NotNil(sub.Monitor)
+	suite.Equal(child.State(), *sub.Monitor)
+
+	nested := sub.Monitor.Subsystems.Get(0)
+	suite.Require().NotNil(nested.Monitor)
+	suite.Equal(StatusBad, nested.Monitor.Subsystems.Get(0).Status)
+
+	// once removed, the parent no longer receives updates
+	suite.NoError(parent.Remove("child"))
+	suite.assertUpdater(grandchild, "leaf").Update(StatusGood, nil)
+	suite.Equal(StatusGood, child.State().Status)
+	suite.Equal(0, parent.State().Subsystems.Len())
+	suite.Equal(suite.nowUTC(), parent.State().LastUpdate)
+}
+
+func (suite *MonitorTestSuite) testNestedInvalid() {
+	child := suite.newMonitor(WithSubsystems(Definition{Name: "local"}))
+	parent := suite.newMonitor(WithSubsystems(Definition{Name: "child", Monitor: child}))
+
+	suite.Error(child.Add(Definition{Name: "self", Monitor: child}))
+	suite.Error(child.Add(Definition{Name: "parent", Monitor: parent}))
+	suite.Equal(1, child.Len())
+	suite.ErrorIs(child.Remove("parent"), ErrNoSuchSubsystem)
+
+	suite.Error(parent.Add(Definition{
+		Name:    "both",
+		Monitor: child,
+		Probe: func(context.Context) (Status, error) {
+			return StatusGood, nil
+		},
+	}))
+
+	// a failed Add must not leave a link to the parent behind
+	suite.assertUpdater(child, "local").Update(StatusWarn, nil)
+	suite.Equal(StatusWarn, parent.State().Status)
+	suite.Equal(1, parent.Len())
+}
+
+func (suite *MonitorTestSuite) TestNested() {
+	suite.Run("Status", suite.testNestedStatus)
+	suite.Run("Invalid", suite.testNestedInvalid)
+}
+
+func (suite *MonitorTestSuite) testDependenciesStatus() {
+	m := suite.newMonitor(
+		WithSubsystems(
+			Definition{Name: "network"},
+			Definition{Name: "database", DependsOn: []Name{"network"}},
+			Definition{Name: "cache", DependsOn: []Name{"database", "network"}, Status: StatusWarn},
+		),
+	)
+
+	suite.Equal(StatusWarn, m.State().Status)
+
+	suite.assertUpdater(m, "network").Update(StatusBad, nil)
+	state := m.State()
+	suite.Equal(StatusBad, state.Status)
+
+	database := state.Subsystems.Get(1)
+	suite.Equal(StatusBad, database.Status)
+	suite.Equal(StatusGood, database.ObservedStatus)
+	suite.Equal(
+		&DependencyError{Subsystem: "database", Dependency: "network", Root: "network"},
+		database.DependencyError,
+	)
+
+	cache := state.Subsystems.Get(2)
+	suite.Equal(StatusBad, cache.Status)
+	suite.Equal(StatusWarn, cache.ObservedStatus)
+	suite.Equal(
+		&DependencyError{Subsystem: "cache", Dependency: "database", Root: "network"},
+		cache.DependencyError,
+	)
+
+	suite.Equal(StatusBad, ErrorStatus(cache.DependencyError))
+	suite.Contains(cache.DependencyError.Error(), "network")
+
+	// a paused dependency no longer affects its dependents
+	suite.NoError(m.Pause("network"))
+	state = m.State()
+	suite.Nil(state.Subsystems.Get(1).DependencyError)
+	suite.Equal(StatusGood, state.Subsystems.Get(1).Status)
+	suite.Equal(StatusWarn, state.Subsystems.Get(2).Status)
+	suite.Equal(StatusWarn, state.Status)
+
+	suite.NoError(m.Resume("network"))
+	suite.assertUpdater(m, "network").Update(StatusGood, nil)
+	state = m.State()
+	suite.Nil(state.Subsystems.Get(2).DependencyError)
+	suite.Equal(StatusWarn, state.Subsystems.Get(2).Status)
+	suite.Equal(StatusWarn, state.Status)
+
+	// warnings do not cascade
+	suite.assertUpdater(m, "database").Update(StatusWarn, nil)
+	suite.Nil(m.State().Subsystems.Get(2).DependencyError)
+}
+
+func (suite *MonitorTestSuite) testDependenciesInvalid() {
+	testCases := []struct {
+		name        string
+		definitions []Definition
+	}{
+		{
+			name: "Missing",
+			definitions: []Definition{
+				{Name: "cache", DependsOn: []Name{"nosuch"}},
+			},
+		},
+		{
+			name: "Self",
+			definitions: []Definition{
+				{Name: "cache", DependsOn: []Name{"cache"}},
+			},
+		},
+		{
+			name: "Cycle",
+			definitions: []Definition{
+				{Name: "a", DependsOn: []Name{"b"}},
+				{Name: "b", DependsOn: []Name{"c"}},
+				{Name: "c", DependsOn: []Name{"a"}},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		suite.Run(testCase.name, func() {
+			m, err := NewMonitor(WithSubsystems(testCase.definitions...))
+			suite.Error(err)
+			suite.Nil(m)
+
+			m = suite.newMonitor()
+			for _, d := range testCase.definitions[:len(testCase.definitions)-1] {
+				// NOTE: dependencies may not exist yet, so use the unsafe method
+				_, err := m.unsafeAdd(d)
+				suite.Require().NoError(err)
+			}
+
+			suite.Error(m.Add(testCase.definitions[len(testCase.definitions)-1]))
+		})
+	}
+
+	suite.Run("Remove", func() {
+		m := suite.newMonitor(
+			WithSubsystems(
+				Definition{Name: "network"},
+				Definition{Name: "cache", DependsOn: []Name{"network"}},
+			),
+		)
+
+		suite.Error(m.Remove("network"))
+		suite.NoError(m.Remove("cache"))
+		suite.NoError(m.Remove("network"))
+	})
+}
+
+func (suite *MonitorTestSuite) testDependenciesSuppressProbes() {
+	probed := make(chan struct{}, 10)
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{Name: "network"},
+			Definition{
+				Name:           "cache",
+				DependsOn:      []Name{"network"},
+				SuppressProbes: true,
+				ProbeInterval:  time.Minute,
+				Probe: func(context.Context) (Status, error) {
+					probed <- struct{}{}
+					return StatusGood, nil
+				},
+			},
+		),
+	)
+
+	suite.assertUpdater(m, "network").Update(StatusBad, nil)
+	suite.assertStart(m)
+	suite.receiveTimer(timers)
+	suite.clock.Add(time.Minute)
+	suite.receiveTimer(timers)
+	suite.Empty(probed)
+
+	suite.assertUpdater(m, "network").Update(StatusGood, nil)
+	suite.clock.Add(time.Minute)
+	suite.receiveTimer(timers)
+	suite.Len(probed, 1)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) TestDependencies() {
+	suite.Run("Status", suite.testDependenciesStatus)
+	suite.Run("Invalid", suite.testDependenciesInvalid)
+	suite.Run("SuppressProbes", suite.testDependenciesSuppressProbes)
+}
+
+func (suite *MonitorTestSuite) receiveEvent(events <-chan Event) Event {
+	select {
+	case e := <-events:
+		return e
+
+	case <-time.After(time.Second):
+		suite.Require().Fail("no event received")
+		return Event{}
+	}
+}
+
+func (suite *MonitorTestSuite) testSubscribeChan() {
+	m := suite.newMonitor(
+		WithSubsystems(Definition{Name: "db"}),
+	)
+
+	events := make(chan Event, 10)
+	cancel := m.SubscribeChan(events)
+
+	suite.assertUpdater(m, "db").Update(StatusGood, nil)
+	e := suite.receiveEvent(events)
+	suite.Equal(StatusGood, e.Previous)
+	suite.Equal(StatusGood, e.Current)
+	suite.Equal(Name("db"), e.Trigger)
+	suite.False(e.Transition())
+	suite.Equal(m.State(), e.State)
+
+	suite.assertUpdater(m, "db").Update(StatusBad, nil)
+	e = suite.receiveEvent(events)
+	suite.Equal(StatusGood, e.Previous)
+	suite.Equal(StatusBad, e.Current)
+	suite.True(e.Transition())
+
+	suite.NoError(m.Pause("db"))
+	e = suite.receiveEvent(events)
+	suite.Equal(StatusBad, e.Previous)
+	suite.Equal(StatusGood, e.Current)
+	suite.Equal(Name("db"), e.Trigger)
+
+	cancel()
+	cancel() // idempotent
+	suite.NoError(m.Resume("db"))
+	suite.Empty(events)
+}
+
+func (suite *MonitorTestSuite) testSubscribeTransitionsOnly() {
+	m := suite.newMonitor(
+		WithSubsystems(Definition{Name: "db"}),
+	)
+
+	events := make(chan Event, 10)
+	defer m.SubscribeChan(events, WithTransitionsOnly())()
+
+	u := suite.assertUpdater(m, "db")
+	u.Update(StatusGood, nil)
+	u.Update(StatusWarn, nil)
+	u.Update(StatusWarn, nil)
+	u.Update(StatusGood, nil)
+
+	e := suite.receiveEvent(events)
+	suite.Equal(StatusGood, e.Previous)
+	suite.Equal(StatusWarn, e.Current)
+
+	e = suite.receiveEvent(events)
+	suite.Equal(StatusWarn, e.Previous)
+	suite.Equal(StatusGood, e.Current)
+	suite.Empty(events)
+}
+
+func (suite *MonitorTestSuite) testSubscribeNonBlocking() {
+	m := suite.newMonitor(
+		WithSubsystems(Definition{Name: "db"}),
+	)
+
+	var (
+		unblock = make(chan struct{})
+		events  = make(chan Event, 10)
+	)
+
+	// neither a full channel nor a stalled callback may block updates
+	defer m.SubscribeChan(make(chan Event))()
+	defer m.Subscribe(
+		func(e Event) {
+			<-unblock
+			events <- e
+		},
+		WithEventBuffer(1),
+	)()
+
+	u := suite.assertUpdater(m, "db")
+	for range 5 {
+		u.Update(StatusWarn, nil)
+	}
+
+	// at most the event being processed plus the buffered event are delivered
+	close(unblock)
+	suite.Equal(StatusWarn, suite.receiveEvent(events).Current)
+	suite.LessOrEqual(len(events), 1)
+}
+
+func (suite *MonitorTestSuite) testSubscribeCallback() {
+	m := suite.newMonitor(
+		WithSubsystems(Definition{Name: "db"}),
+	)
+
+	events := make(chan Event, 10)
+	cancel := m.Subscribe(func(e Event) {
+		events <- e
+	})
+
+	suite.assertUpdater(m, "db").Update(StatusBad, nil)
+	e := suite.receiveEvent(events)
+	suite.Equal(StatusGood, e.Previous)
+	suite.Equal(StatusBad, e.Current)
+	suite.Equal(Name("db"), e.Trigger)
+
+	suite.NoError(m.Add(Definition{Name: "cache"}))
+	e = suite.receiveEvent(events)
+	suite.Equal(Name("cache"), e.Trigger)
+
+	cancel()
+	cancel() // idempotent
+	suite.assertUpdater(m, "db").Update(StatusGood, nil)
+	suite.Empty(events)
+}
+
+func (suite *MonitorTestSuite) testSubscribeChanged() {
+	m := suite.newMonitor(
+		WithSubsystems(Definition{Name: "db"}),
+	)
+
+	changed := m.Changed()
+	suite.Equal(changed, m.Changed())
+	select {
+	case <-changed:
+		suite.Fail("the channel should not be closed before an update")
+	default:
+	}
+
+	suite.assertUpdater(m, "db").Update(StatusBad, nil)
+	select {
+	case <-changed:
+	default:
+		suite.Fail("the channel should be closed after an update")
+	}
+
+	next := m.Changed()
+	suite.NotEqual(changed, next)
+	select {
+	case <-next:
+		suite.Fail("a new channel should not be closed before the next update")
+	default:
+	}
+}
+
+func (suite *MonitorTestSuite) TestSubscribe() {
+	suite.Run("Chan", suite.testSubscribeChan)
+	suite.Run("TransitionsOnly", suite.testSubscribeTransitionsOnly)
+	suite.Run("NonBlocking", suite.testSubscribeNonBlocking)
+	suite.Run("Callback", suite.testSubscribeCallback)
+	suite.Run("Changed", suite.testSubscribeChanged)
+}
+
+func (suite *MonitorTestSuite) testHistoryTransitions() {
+	m := suite.newMonitor(
+		WithSubsystems(
+			Definition{Name: "db", HistorySize: 3},
+		),
+	)
+
+	start := suite.nowUTC()
+	u := suite.assertUpdater(m, "db")
+
+	suite.clock.Add(time.Second)
+	u.Update(StatusGood, nil) // not a transition
+
+	suite.clock.Add(time.Second)
+	u.Update(StatusBad, errors.New("connection refused"))
+	bad := suite.nowUTC()
+
+	suite.clock.Add(time.Second)
+	u.Update(StatusBad, errors.New("connection refused")) // not a transition
+
+	suite.clock.Add(time.Second)
+	u.Update(StatusGood, nil)
+	good := suite.nowUTC()
+
+	h, err := m.History("db")
+	suite.Require().NoError(err)
+	suite.Equal(
+		[]HistoryEntry{
+			{Timestamp: start, Status: StatusGood},
+			{Timestamp: bad, Status: StatusBad, Error: "connection refused"},
+			{Timestamp: good, Status: StatusGood},
+		},
+		h,
+	)
+
+	// the oldest transition is discarded
+	suite.clock.Add(time.Second)
+	u.Update(StatusWarn, nil)
+	h, err = m.History("db")
+	suite.Require().NoError(err)
+	suite.Require().Len(h, 3)
+	suite.Equal(bad, h[0].Timestamp)
+	suite.Equal(StatusWarn, h[2].Status)
+}
+
+func (suite *MonitorTestSuite) testHistorySize() {
+	m := suite.newMonitor(
+		WithDefaultHistorySize(2),
+		WithSubsystems(
+			Definition{Name: "default"},
+			Definition{Name: "disabled", HistorySize: -1},
+		),
+	)
+
+	for _, s := range []Status{StatusWarn, StatusBad, StatusGood} {
+		suite.clock.Add(time.Second)
+		suite.assertUpdater(m, "default").Update(s, nil)
+		suite.assertUpdater(m, "disabled").Update(s, nil)
+	}
+
+	h, err := m.History("default")
+	suite.NoError(err)
+	suite.Len(h, 2)
+
+	h, err = m.History("disabled")
+	suite.NoError(err)
+	suite.Empty(h)
+
+	_, err = m.History("nosuch")
+	suite.ErrorIs(err, ErrNoSuchSubsystem)
+}
+
+func (suite *MonitorTestSuite) testHistoryDisabledByDefault() {
+	m := suite.newMonitor(
+		WithDefaultHistorySize(0),
+		WithSubsystems(
+			Definition{Name: "default"},
+			Definition{Name: "enabled", HistorySize: 5},
+		),
+	)
+
+	h, err := m.History("default")
+	suite.NoError(err)
+	suite.Empty(h)
+
+	h, err = m.History("enabled")
+	suite.NoError(err)
+	suite.Len(h, 1)
+}
+
+func (suite *MonitorTestSuite) TestHistory() {
+	suite.Run("Transitions", suite.testHistoryTransitions)
+	suite.Run("Size", suite.testHistorySize)
+	suite.Run("DisabledByDefault", suite.testHistoryDisabledByDefault)
+}
+
+func (suite *MonitorTestSuite) testFlappingStatus() {
+	m := suite.newMonitor(
+		WithSubsystems(
+			Definition{
+				Name:          "db",
+				FlapThreshold: 2,
+				FlapWindow:    time.Minute,
+			},
+		),
+	)
+
+	u := suite.assertUpdater(m, "db")
+	for _, s := range []Status{StatusBad, StatusGood} {
+		suite.clock.Add(time.Second)
+		u.Update(s, nil)
+	}
+
+	current := m.State().Subsystems.Get(0)
+	suite.False(current.Flapping)
+
+	suite.clock.Add(time.Second)
+	u.Update(StatusBad, nil)
+	expected := suite.newExpectedSubsystem(Definition{Name: "db", Status: StatusWarn})
+	expected.ObservedStatus = StatusBad
+	expected.LastUpdate = suite.nowUTC()
+	expected.Flapping = true
+	suite.assertState(m, StatusWarn, expected)
+
+	// updates without a transition don't end the quiet period early
+	suite.clock.Add(time.Second)
+	u.Update(StatusBad, nil)
+	expected.LastUpdate = suite.nowUTC()
+	suite.assertState(m, StatusWarn, expected)
+
+	// once the quiet period elapses, the next update clears the flapping state
+	suite.clock.Add(time.Minute)
+	u.Update(StatusBad, nil)
+	expected.Status = StatusBad
+	expected.LastUpdate = suite.nowUTC()
+	expected.Flapping = false
+	suite.assertState(m, StatusBad, expected)
+}
+
+func (suite *MonitorTestSuite) testFlappingWindow() {
+	m := suite.newMonitor(
+		WithSubsystems(
+			Definition{
+				Name:           "db",
+				FlapThreshold:  2,
+				FlapWindow:     time.Minute,
+				FlappingStatus: StatusBad,
+			},
+		),
+	)
+
+	// transitions spread out beyond the window are not flapping
+	u := suite.assertUpdater(m, "db")
+	for _, s := range []Status{StatusWarn, StatusGood, StatusWarn, StatusGood} {
+		suite.clock.Add(40 * time.Second)
+		u.Update(s, nil)
+
+		current := m.State().Subsystems.Get(0)
+		suite.False(current.Flapping)
+	}
+
+	for _, s := range []Status{StatusWarn, StatusGood} {
+		suite.clock.Add(time.Second)
+		u.Update(s, nil)
+	}
+
+	current := m.State().Subsystems.Get(0)
+	suite.True(current.Flapping)
+	suite.Equal(StatusBad, current.Status)
+	suite.Equal(StatusGood, current.ObservedStatus)
+}
+
+func (suite *MonitorTestSuite) testFlappingQuietPeriod() {
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{
+				Name:            "db",
+				FlapThreshold:   1,
+				FlapQuietPeriod: 30 * time.Second,
+			},
+		),
+	)
+
+	suite.assertStart(m)
+	u := suite.assertUpdater(m, "db")
+	u.Update(StatusBad, nil)
+	u.Update(StatusGood, nil)
+	suite.Equal(30*time.Second, suite.receiveTimer(timers))
+
+	// another transition restarts the quiet period
+	suite.clock.Add(10 * time.Second)
+	u.Update(StatusBad, nil)
+	suite.Equal(30*time.Second, suite.receiveTimer(timers))
+
+	current := m.State().Subsystems.Get(0)
+	suite.True(current.Flapping)
+	suite.Equal(StatusWarn, current.Status)
+
+	events := make(chan Event, 10)
+	defer m.SubscribeChan(events)()
+	suite.clock.Add(30 * time.Second)
+	e := suite.receiveEvent(events)
+	suite.Equal(StatusWarn, e.Previous)
+	suite.Equal(StatusBad, e.Current)
+	suite.Equal(Name("db"), e.Trigger)
+	suite.False(e.State.Subsystems.Get(0).Flapping)
+
+	suite.assertNoTimer(timers)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) TestFlapping() {
+	suite.Run("Status", suite.testFlappingStatus)
+	suite.Run("Window", suite.testFlappingWindow)
+	suite.Run("QuietPeriod", suite.testFlappingQuietPeriod)
+}
+
+func (suite *MonitorTestSuite) testStatsSinceStart() {
+	m := suite.newMonitor(
+		WithSubsystems(
+			Definition{Name: "db"},
+			Definition{Name: "cache", DependsOn: []Name{"db"}},
+		),
+	)
+
+	// nothing is collected before the Monitor starts
+	u := suite.assertUpdater(m, "db")
+	u.Update(StatusBad, nil)
+	suite.clock.Add(time.Minute)
+	stats, err := m.Stats("db")
+	suite.NoError(err)
+	suite.Zero(stats.Since)
+	suite.Empty(stats.TimeInStatus)
+
+	suite.assertStart(m)
+	started := suite.nowUTC()
+	suite.clock.Add(time.Minute)
+	u.Update(StatusGood, nil)
+	suite.clock.Add(3 * time.Minute)
+
+	stats, err = m.Stats("db")
+	suite.NoError(err)
+	suite.Equal(started, stats.Since)
+	suite.Equal(time.Minute, stats.TimeInStatus[StatusBad])
+	suite.Equal(3*time.Minute, stats.TimeInStatus[StatusGood])
+	suite.Equal(1, stats.Transitions)
+	suite.Equal(1, stats.Recoveries)
+	suite.Equal(time.Minute, stats.MeanTimeToRecover)
+	suite.InDelta(75.0, stats.Availability, 0.001)
+
+	// statistics are based on the effective status
+	stats, err = m.Stats("cache")
+	suite.NoError(err)
+	suite.Equal(time.Minute, stats.TimeInStatus[StatusBad])
+	suite.Equal(1, stats.Recoveries)
+
+	_, err = m.Stats("nosuch")
+	suite.ErrorIs(err, ErrNoSuchSubsystem)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) testStatsAdded() {
+	m := suite.newMonitor()
+	suite.assertStart(m)
+
+	suite.clock.Add(time.Minute)
+	added := suite.nowUTC()
+	suite.NoError(m.Add(Definition{Name: "db", Status: StatusWarn}))
+	suite.clock.Add(time.Minute)
+
+	stats, err := m.Stats("db")
+	suite.NoError(err)
+	suite.Equal(added, stats.Since)
+	suite.Equal(time.Minute, stats.TimeInStatus[StatusWarn])
+	suite.Zero(stats.Availability)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) TestStats() {
+	suite.Run("SinceStart", suite.testStatsSinceStart)
+	suite.Run("Added", suite.testStatsAdded)
+}
+
+func (suite *MonitorTestSuite) testStartupGracePeriodCapped() {
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{
+				Name:               "db",
+				StartupGracePeriod: time.Minute,
+				StartupStatus:      StatusWarn,
+			},
+		),
+	)
+
+	// the grace period doesn't begin until the Monitor starts
+	u := suite.assertUpdater(m, "db")
+	u.Update(StatusBad, nil)
+	suite.Equal(StatusBad, m.State().Status)
+
+	suite.assertStart(m)
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+
+	current := m.State().Subsystems.Get(0)
+	suite.True(current.Starting)
+	suite.Equal(StatusWarn, current.Status)
+	suite.Equal(StatusBad, current.ObservedStatus)
+	suite.Equal(StatusWarn, m.State().Status)
+
+	events := make(chan Event, 10)
+	defer m.SubscribeChan(events)()
+	suite.clock.Add(time.Minute)
+	e := suite.receiveEvent(events)
+	suite.Equal(StatusWarn, e.Previous)
+	suite.Equal(StatusBad, e.Current)
+	suite.False(e.State.Subsystems.Get(0).Starting)
+
+	// the grace period only happens once
+	suite.assertShutdown(m)
+	suite.assertStart(m)
+	suite.assertNoTimer(timers)
+	suite.Equal(StatusBad, m.State().Status)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) testStartupGracePeriodEndsEarly() {
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{
+				Name:               "db",
+				StartupGracePeriod: time.Minute,
+			},
+		),
+	)
+
+	suite.assertStart(m)
+	suite.receiveTimer(timers)
+
+	// failures are ignored entirely by default
+	u := suite.assertUpdater(m, "db")
+	u.Update(StatusBad, nil)
+	suite.Equal(StatusGood, m.State().Status)
+	suite.True(m.State().Subsystems.Get(0).Starting)
+
+	u.Update(StatusGood, nil)
+	suite.False(m.State().Subsystems.Get(0).Starting)
+
+	u.Update(StatusBad, nil)
+	suite.Equal(StatusBad, m.State().Status)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) testStartupGracePeriodDefault() {
+	m, timers := suite.newProbingMonitor(
+		WithDefaultStartupGracePeriod(time.Minute),
+		WithSubsystems(
+			Definition{Name: "default", Status: StatusBad},
+			Definition{Name: "disabled", Status: StatusBad, StartupGracePeriod: -1},
+		),
+	)
+
+	suite.assertStart(m)
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+	suite.assertNoTimer(timers)
+
+	state := m.State()
+	suite.True(state.Subsystems.Get(0).Starting)
+	suite.Equal(StatusGood, state.Subsystems.Get(0).Status)
+	suite.False(state.Subsystems.Get(1).Starting)
+	suite.Equal(StatusBad, state.Subsystems.Get(1).Status)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) testStartupGracePeriodPaused() {
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{
+				Name:               "db",
+				Status:             StatusBad,
+				StartupGracePeriod: time.Minute,
+			},
+		),
+	)
+
+	suite.assertStart(m)
+	suite.receiveTimer(timers)
+	suite.clock.Add(20 * time.Second)
+	suite.NoError(m.Pause("db"))
+
+	// resuming only waits out the remainder of the grace period
+	suite.NoError(m.Resume("db"))
+	suite.Equal(40*time.Second, suite.receiveTimer(timers))
+	suite.True(m.State().Subsystems.Get(0).Starting)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) testStartupGracePeriodDependencies() {
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{Name: "db", StartupGracePeriod: -1},
+			Definition{Name: "app", DependsOn: []Name{"db"}, StartupGracePeriod: time.Minute},
+			Definition{Name: "web", DependsOn: []Name{"app"}, StartupGracePeriod: -1},
+		),
+	)
+
+	suite.assertStart(m)
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+	suite.assertNoTimer(timers)
+
+	// a starting dependency is capped before its dependents see it
+	suite.assertUpdater(m, "app").Update(StatusBad, nil)
+	app, _ := m.State().Subsystems.find("app")
+	suite.True(app.Starting)
+	suite.Equal(StatusGood, app.Status)
+	web, _ := m.State().Subsystems.find("web")
+	suite.Equal(StatusGood, web.Status)
+
+	// a failing dependency takes precedence over the grace period
+	suite.assertUpdater(m, "db").Update(StatusBad, nil)
+	app, _ = m.State().Subsystems.find("app")
+	suite.True(app.Starting)
+	suite.Equal(StatusBad, app.Status)
+	suite.Require().NotNil(app.DependencyError)
+	suite.Equal(Name("db"), app.DependencyError.Root)
+
+	web, _ = m.State().Subsystems.find("web")
+	suite.Equal(StatusBad, web.Status)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) TestStartupGracePeriod() {
+	suite.Run("Capped", suite.testStartupGracePeriodCapped)
+	suite.Run("EndsEarly", suite.testStartupGracePeriodEndsEarly)
+	suite.Run("Default", suite.testStartupGracePeriodDefault)
+	suite.Run("Paused", suite.testStartupGracePeriodPaused)
+	suite.Run("Dependencies", suite.testStartupGracePeriodDependencies)
+}
+
+func (suite *MonitorTestSuite) assertKindStatus(state MonitorState, k Kind, expected Status) {
+	status, ok := state.StatusOf(k)
+	suite.True(ok)
+	suite.Equal(expected, status)
+}
+
+func (suite *MonitorTestSuite) testKindsStatus() {
+	m := suite.newMonitor(
+		WithGroups(GroupDefinition{Name: "storage"}),
+		WithSubsystems(
+			Definition{Name: "process"},
+			Definition{Name: "db", Kinds: []Kind{KindReadiness}, Group: "storage"},
+			Definition{Name: "cache", Kinds: []Kind{KindReadiness, "cache"}, NonCritical: true},
+			Definition{Name: "migrations", Kinds: []Kind{KindStartup}},
+		),
+	)
+
+	state := m.State()
+	suite.Equal(
+		map[Kind]Status{
+			KindLiveness:  StatusGood,
+			KindReadiness: StatusGood,
+			KindStartup:   StatusGood,
+			"cache":       StatusGood,
+		},
+		maps.Collect(state.Kinds.All()),
+	)
+
+	suite.clock.Add(time.Second)
+	suite.assertUpdater(m, "db").Update(StatusBad, nil)
+	suite.assertUpdater(m, "cache").Update(StatusBad, nil)
+	suite.assertUpdater(m, "migrations").Update(StatusWarn, nil)
+
+	state = m.State()
+	suite.Equal(StatusBad, state.Status)
+	suite.assertKindStatus(state, KindLiveness, StatusGood)
+	suite.assertKindStatus(state, KindReadiness, StatusBad)
+	suite.assertKindStatus(state, KindStartup, StatusWarn)
+	suite.assertKindStatus(state, "cache", StatusWarn)
+	suite.assertKindStatus(state, "", StatusBad)
+
+	// unknown kinds never appear healthy
+	status, ok := state.StatusOf("nosuch")
+	suite.False(ok)
+	suite.Equal(StatusBad, status)
+
+	// paused subsystems don't affect any kind
+	suite.NoError(m.Pause("db"))
+	suite.assertKindStatus(m.State(), KindReadiness, StatusWarn)
+
+	// removing a subsystem removes its custom kinds
+	suite.NoError(m.Remove("cache"))
+	_, ok = m.State().StatusOf("cache")
+	suite.False(ok)
+}
+
+func (suite *MonitorTestSuite) testKindsForKind() {
+	m := suite.newMonitor(
+		WithGroups(
+			GroupDefinition{Name: "storage"},
+			GroupDefinition{Name: "network"},
+		),
+		WithSubsystems(
+			Definition{Name: "process"},
+			Definition{Name: "db", Kinds: []Kind{KindReadiness}, Group: "storage"},
+			Definition{Name: "dns", Kinds: []Kind{KindStartup}, Group: "network"},
+		),
+	)
+
+	suite.assertUpdater(m, "db").Update(StatusBad, nil)
+	state := m.State()
+	suite.Equal(state, state.ForKind(""))
+
+	view := state.ForKind(KindLiveness)
+	suite.Equal(StatusGood, view.Status)
+	suite.Equal([]Name{"process"}, suite.subsystemNames(view))
+	suite.Zero(view.Groups.Len())
+
+	view = state.ForKind(KindReadiness)
+	suite.Equal(StatusBad, view.Status)
+	suite.Equal([]Name{"process", "db"}, suite.subsystemNames(view))
+	suite.Require().Equal(1, view.Groups.Len())
+	suite.Equal(Name("storage"), view.Groups.Get(0).Name)
+
+	view = state.ForKind("nosuch")
+	suite.Equal(StatusBad, view.Status)
+	suite.Zero(view.Subsystems.Len())
+	suite.Zero(view.Groups.Len())
+
+	// the original state is unchanged
+	suite.Equal(3, state.Subsystems.Len())
+	suite.Equal(2, state.Groups.Len())
+}
+
+func (suite *MonitorTestSuite) subsystemNames(state MonitorState) (names []Name) {
+	for s := range state.Subsystems.All() {
+		names = append(names, s.Name)
+	}
+
+	return
+}
+
+func (suite *MonitorTestSuite) testKindsImmutable() {
+	kinds := []Kind{KindReadiness}
+	m := suite.newMonitor(
+		WithSubsystems(
+			Definition{Name: "db", Kinds: kinds},
+		),
+	)
+
+	// changing the definition's kinds doesn't affect the monitor
+	kinds[0] = KindStartup
+	suite.Equal([]Kind{KindReadiness}, m.State().Subsystems.Get(0).Kinds)
+	suite.assertKindStatus(m.State(), KindReadiness, StatusGood)
+}
+
+func (suite *MonitorTestSuite) TestKinds() {
+	suite.Run("Status", suite.testKindsStatus)
+	suite.Run("ForKind", suite.testKindsForKind)
+	suite.Run("Immutable", suite.testKindsImmutable)
+}
+
+func (suite *MonitorTestSuite) testOverridesManual() {
+	m := suite.newMonitor(
+		WithSubsystems(
+			Definition{Name: "db", Status: StatusBad},
+			Definition{Name: "app", DependsOn: []Name{"db"}},
+		),
+	)
+
+	suite.Equal(StatusBad, m.State().Status)
+	suite.NoError(m.Override("db", StatusWarn, "upgrade", time.Time{}))
+
+	state := m.State()
+	suite.Equal(StatusWarn, state.Status)
+	db := state.Subsystems.Get(0)
+	suite.Equal(StatusWarn, db.Status)
+	suite.Equal(StatusBad, db.ObservedStatus)
+	suite.Equal(&Override{Status: StatusWarn, Reason: "upgrade"}, db.Override)
+
+	// dependents see the overridden status
+	suite.Equal(StatusGood, state.Subsystems.Get(1).Status)
+
+	// updates are still recorded underneath the override
+	suite.assertUpdater(m, "db").Update(StatusGood, nil)
+	db = m.State().Subsystems.Get(0)
+	suite.Equal(StatusWarn, db.Status)
+	suite.Equal(StatusGood, db.ObservedStatus)
+
+	suite.NoError(m.ClearOverride("db"))
+	suite.Nil(m.State().Subsystems.Get(0).Override)
+	suite.Equal(StatusGood, m.State().Status)
+
+	suite.ErrorIs(m.Override("nosuch", StatusGood, "", time.Time{}), ErrNoSuchSubsystem)
+	suite.ErrorIs(m.Exclude("nosuch", "", time.Time{}), ErrNoSuchSubsystem)
+	suite.ErrorIs(m.ClearOverride("nosuch"), ErrNoSuchSubsystem)
+}
+
+func (suite *MonitorTestSuite) testOverridesExclude() {
+	m := suite.newMonitor(
+		WithSubsystems(
+			Definition{Name: "db", Status: StatusBad},
+			Definition{Name: "app", DependsOn: []Name{"db"}},
+		),
+	)
+
+	suite.Equal(StatusBad, m.State().Status)
+	suite.NoError(m.Exclude("db", "migration", time.Time{}))
+
+	state := m.State()
+	suite.Equal(StatusGood, state.Status)
+	suite.assertKindStatus(state, KindReadiness, StatusGood)
+	suite.Equal(StatusBad, state.Subsystems.Get(0).Status)
+	suite.True(state.Subsystems.Get(0).Override.Exclude)
+	suite.Equal(StatusGood, state.Subsystems.Get(1).Status)
+	suite.Nil(state.Subsystems.Get(1).DependencyError)
+}
+
+func (suite *MonitorTestSuite) testOverridesExpire() {
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{Name: "db", Status: StatusBad},
+		),
+	)
+
+	suite.NoError(m.Override("db", StatusGood, "known issue", suite.clock.Now().Add(time.Minute)))
+	suite.Equal(StatusGood, m.State().Status)
+	suite.Equal(suite.nowUTC().Add(time.Minute), m.State().Subsystems.Get(0).Override.Until)
+
+	suite.assertStart(m)
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+
+	events := make(chan Event, 10)
+	defer m.SubscribeChan(events)()
+	suite.clock.Add(time.Minute)
+	e := suite.receiveEvent(events)
+	suite.Equal(StatusGood, e.Previous)
+	suite.Equal(StatusBad, e.Current)
+	suite.Nil(e.State.Subsystems.Get(0).Override)
+
+	suite.assertNoTimer(timers)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) testOverridesMaintenance() {
+	start := suite.clock.Now().Add(time.Minute)
+	m, timers := suite.newProbingMonitor(
+		WithSubsystems(
+			Definition{
+				Name:   "db",
+				Status: StatusBad,
+				Maintenance: []MaintenanceWindow{
+					{
+						Start:  start,
+						End:    start.Add(time.Hour),
+						Status: StatusWarn,
+						Reason: "scheduled upgrade",
+					},
+				},
+			},
+		),
+	)
+
+	suite.Nil(m.State().Subsystems.Get(0).Override)
+	suite.assertStart(m)
+	suite.Equal(time.Minute, suite.receiveTimer(timers))
+
+	events := make(chan Event, 10)
+	defer m.SubscribeChan(events)()
+	suite.clock.Add(time.Minute)
+	e := suite.receiveEvent(events)
+	suite.Equal(StatusWarn, e.Current)
+	suite.Equal(
+		&Override{
+			Status:      StatusWarn,
+			Reason:      "scheduled upgrade",
+			Until:       start.Add(time.Hour),
+			Maintenance: true,
+		},
+		e.State.Subsystems.Get(0).Override,
+	)
+
+	suite.Equal(time.Hour, suite.receiveTimer(timers))
+	suite.clock.Add(time.Hour)
+	e = suite.receiveEvent(events)
+	suite.Equal(StatusBad, e.Current)
+	suite.Nil(e.State.Subsystems.Get(0).Override)
+
+	suite.assertNoTimer(timers)
+	suite.assertShutdown(m)
+}
+
+func (suite *MonitorTestSuite) testOverridesScheduleMaintenance() {
+	m := suite.newMonitor(
+		WithSubsystems(
+			Definition{Name: "db", Status: StatusBad},
+		),
+	)
+
+	now := suite.clock.Now()
+	suite.Error(m.ScheduleMaintenance("db", MaintenanceWindow{Start: now, End: now}))
+	suite.ErrorIs(m.ScheduleMaintenance("nosuch", MaintenanceWindow{Start: now, End: now.Add(time.Hour)}), ErrNoSuchSubsystem)
+
+	suite.NoError(m.ScheduleMaintenance("db", MaintenanceWindow{Start: now, End: now.Add(time.Hour), Exclude: true}))
+	suite.Equal(StatusGood, m.State().Status)
+	suite.True(m.State().Subsystems.Get(0).Override.Maintenance)
+
+	// a manual override takes precedence over maintenance
+	suite.NoError(m.Override("db", StatusWarn, "", time.Time{}))
+	suite.Equal(StatusWarn, m.State().Status)
+	suite.False(m.State().Subsystems.Get(0).Override.Maintenance)
+
+	_, err := NewMonitor(
+		WithSubsystems(
+			Definition{
+				Name:        "invalid",
+				Maintenance: []MaintenanceWindow{{Start: now.Add(time.Hour), End: now}},
+			},
+		),
+	)
+
+	suite.Error(err)
+}
+
+func (suite *MonitorTestSuite) TestOverrides() {
+	suite.Run("Manual", suite.testOverridesManual)
+	suite.Run("Exclude", suite.testOverridesExclude)
+	suite.Run("Expire", suite.testOverridesExpire)
+	suite.Run("Maintenance", suite.testOverridesMaintenance)
+	suite.Run("ScheduleMaintenance", suite.testOverridesScheduleMaintenance)
+}
+
+func (suite *MonitorTestSuite) TestProbeNow() {
+	var probes atomic.Int32
+	m := suite.newMonitor(
+		WithSubsystems(
+			Definition{Name: "updated"},
+			Definition{
+				Name: "probed",
+				Probe: func(context.Context) (Status, error) {
+					probes.Add(1)
+					return StatusBad, nil
+				},
+			},
+		),
+	)
+
+	// probes can run even if the Monitor isn't running
+	suite.clock.Add(time.Second)
+	s, err := m.ProbeNow(context.Background(), "probed")
+	suite.NoError(err)
+	suite.Equal(StatusBad, s.Status)
+	suite.Equal(suite.nowUTC(), s.LastUpdate)
+	suite.Equal(int32(1), probes.Load())
+	suite.Equal(StatusBad, m.State().Status)
+
+	_, err = m.ProbeNow(context.Background(), "updated")
+	suite.ErrorIs(err, ErrNoProbe)
+
+	_, err = m.ProbeNow(context.Background(), "nosuch")
+	suite.ErrorIs(err, ErrNoSuchSubsystem)
+
+	suite.NoError(m.Pause("probed"))
+	_, err = m.ProbeNow(context.Background(), "probed")
+	suite.ErrorIs(err, ErrSubsystemPaused)
+	suite.NoError(m.Resume("probed"))
+
+	// results are discarded if the context is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = m.ProbeNow(ctx, "probed")
+	suite.ErrorIs(err, context.Canceled)
+
+	_, err = m.Subsystem("nosuch")
+	suite.ErrorIs(err, ErrNoSuchSubsystem)
+}
+
 func TestMonitor(t *testing.T) {
 	suite.Run(t, new(MonitorTestSuite))
 }
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// parentLink records a subsystem in a parent Monitor that tracks a child Monitor.
+type parentLink struct {
+	monitor *Monitor
+	tracker *subsystemTracker
+}
+
+// parents holds the set of subsystems in other Monitors that track a given Monitor.
+//
+// This type has its own lock, distinct from the monitor lock. Locks are always acquired
+// from child to parent: a child holds its monitor lock while pushing state to its parents,
+// and a parent holds its monitor lock while attaching to or detaching from a child. Neither
+// path holds this lock while acquiring a monitor lock.
+type parents struct {
+	lock  sync.Mutex
+	links []parentLink
+}
+
+// add registers a parent subsystem.
+func (p *parents) add(link parentLink) {
+	defer p.lock.Unlock()
+	p.lock.Lock()
+	p.links = append(p.links, link)
+}
+
+// remove deregisters a parent subsystem.
+func (p *parents) remove(sst *subsystemTracker) {
+	defer p.lock.Unlock()
+	p.lock.Lock()
+	p.links = slices.DeleteFunc(p.links, func(link parentLink) bool {
+		return link.tracker == sst
+	})
+}
+
+// snapshot returns a copy of the current set of parent subsystems.
+func (p *parents) snapshot() []parentLink {
+	defer p.lock.Unlock()
+	p.lock.Lock()
+	return slices.Clone(p.links)
+}
+
+// isAncestor tests if candidate is m or any Monitor that m is nested within,
+// directly or indirectly.
+func (m *Monitor) isAncestor(candidate *Monitor) bool {
+	visited := make(map[*Monitor]bool)
+	pending := []*Monitor{m}
+	for len(pending) > 0 {
+		next := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		switch {
+		case next == candidate:
+			return true
+
+		case visited[next]:
+			continue
+		}
+
+		visited[next] = true
+		for _, link := range next.parents.snapshot() {
+			pending = append(pending, link.monitor)
+		}
+	}
+
+	return false
+}
+
+// unsafeAttach links a subsystem that tracks a child Monitor to that child. The
+// subsystem's current state is set from the child's current state. If the subsystem
+// does not track a child Monitor, this method does nothing.
+//
+// This method must be executed under the monitor lock or in a situation where no
+// concurrent invocation is possible.
+func (m *Monitor) unsafeAttach(sst *subsystemTracker) error {
+	child := sst.definition.Monitor
+	if child == nil {
+		return nil
+	}
+
+	if m.isAncestor(child) {
+		return fmt.Errorf("nesting the monitor in subsystem [%s] would create a cycle", sst.definition.Name)
+	}
+
+	child.parents.add(parentLink{
+		monitor: m,
+		tracker: sst,
+	})
+
+	// NOTE: the child may push a new state between registration and this load,
+	// which is harmless since the child's state is always a complete snapshot
+	state := child.State()
+	sst.current.ObservedStatus = state.Status
+	sst.current.Monitor = &state
+	return nil
+}
+
+// unsafeDetach unlinks a subsystem from its child Monitor, if any.
+//
+// This method must be executed under the monitor lock or in a situation where no
+// concurrent invocation is possible.
+func (m *Monitor) unsafeDetach(sst *subsystemTracker) {
+	if child := sst.definition.Monitor; child != nil {
+		child.parents.remove(sst)
+	}
+}
+
+// notifyParents pushes a new state to each subsystem that tracks this Monitor.
+//
+// This method is executed under this Monitor's lock, and acquires each parent's lock.
+func (m *Monitor) notifyParents(state MonitorState) {
+	for _, link := range m.parents.snapshot() {
+		link.tracker.updateMonitor(state)
+	}
+}
@@ -0,0 +1,284 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Override describes an operator-imposed change to how a subsystem is reported, either
+// set manually or from a scheduled maintenance window. The subsystem's observed status
+// is still recorded while an override is in effect.
+type Override struct {
+	// Status is the effective status forced onto the subsystem. This field is ignored
+	// when Exclude is set.
+	Status Status `json:"status" yaml:"status"`
+
+	// Exclude indicates that the subsystem is excluded from aggregation, as if it were
+	// paused, rather than having its status forced.
+	Exclude bool `json:"exclude" yaml:"exclude"`
+
+	// Reason is the optional, human-readable reason for the override.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+
+	// Until is the time at which the override expires. The zero time indicates an
+	// override that lasts until it is cleared.
+	Until time.Time `json:"until,omitempty" yaml:"until,omitempty"`
+
+	// Maintenance indicates whether this override comes from a maintenance window.
+	Maintenance bool `json:"maintenance" yaml:"maintenance"`
+}
+
+// MaintenanceWindow is a scheduled period during which a subsystem is overridden.
+type MaintenanceWindow struct {
+	// Start is the beginning of the window, inclusive.
+	Start time.Time
+
+	// End is the end of the window, exclusive. This must be after Start.
+	End time.Time
+
+	// Status is the effective status forced onto the subsystem during this window.
+	// This field is ignored when Exclude is set.
+	Status Status
+
+	// Exclude indicates that the subsystem is excluded from aggregation during this
+	// window rather than having its status forced.
+	Exclude bool
+
+	// Reason is the optional, human-readable reason for the maintenance.
+	Reason string
+}
+
+// contains tests if the given time falls within this window.
+func (mw MaintenanceWindow) contains(t time.Time) bool {
+	return !t.Before(mw.Start) && t.Before(mw.End)
+}
+
+// validateMaintenance checks the maintenance windows for the given subsystem.
+func validateMaintenance(n Name, windows ...MaintenanceWindow) error {
+	for _, w := range windows {
+		if !w.End.After(w.Start) {
+			return fmt.Errorf("the maintenance window [%s, %s) for subsystem [%s] is empty", w.Start, w.End, n)
+		}
+	}
+
+	return nil
+}
+
+// initializeOverrides sets up this subsystem's maintenance windows.
+func (sst *subsystemTracker) initializeOverrides() {
+	sst.overrides = make(chan struct{}, 1)
+	sst.windows = slices.Clone(sst.definition.Maintenance)
+}
+
+// active tests if this subsystem contributes to aggregation, i.e. it is neither
+// paused nor excluded by an override.
+func (s Subsystem) active() bool {
+	return !s.Paused && (s.Override == nil || !s.Override.Exclude)
+}
+
+// unsafeApplyOverride determines the override in effect for this subsystem at the given
+// time, if any, and applies it to the effective status. Expired overrides and maintenance
+// windows are discarded.
+//
+// This method must be executed under the monitor lock.
+func (sst *subsystemTracker) unsafeApplyOverride(t time.Time) {
+	if sst.override != nil && !sst.override.Until.IsZero() && !t.Before(sst.override.Until) {
+		sst.override = nil
+	}
+
+	sst.windows = slices.DeleteFunc(sst.windows, func(w MaintenanceWindow) bool {
+		return !t.Before(w.End)
+	})
+
+	// always allocate a new Override, as older snapshots may refer to the current one
+	sst.current.Override = nil
+	if sst.override != nil {
+		o := *sst.override
+		sst.current.Override = &o
+	} else {
+		for _, w := range sst.windows {
+			if w.contains(t) {
+				sst.current.Override = &Override{
+					Status:      w.Status,
+					Exclude:     w.Exclude,
+					Reason:      w.Reason,
+					Until:       w.End,
+					Maintenance: true,
+				}
+
+				break
+			}
+		}
+	}
+
+	if o := sst.current.Override; o != nil && !o.Exclude {
+		sst.current.Status = o.Status
+		sst.current.DependencyError = nil
+	}
+}
+
+// nextOverrideChange returns the next time after now at which the override in effect
+// for this subsystem may change. If there is no such time, this method returns the
+// zero time.
+func (sst *subsystemTracker) nextOverrideChange(now time.Time) (next time.Time) {
+	defer sst.lock.Unlock()
+	sst.lock.Lock()
+
+	consider := func(t time.Time) {
+		if t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+
+	if sst.override != nil {
+		consider(sst.override.Until)
+	}
+
+	for _, w := range sst.windows {
+		consider(w.Start)
+		consider(w.End)
+	}
+
+	return
+}
+
+// overrideTask recomputes this Monitor's state each time an override or maintenance
+// window for this subsystem begins or ends. This task runs until the given context
+// is canceled.
+func (sst *subsystemTracker) overrideTask(ctx context.Context) {
+	for {
+		var (
+			now    = sst.now()
+			timeCh <-chan time.Time
+			stop   = func() bool { return false }
+		)
+
+		if next := sst.nextOverrideChange(now); !next.IsZero() {
+			timeCh, stop = sst.newTimer(next.Sub(now))
+		}
+
+		select {
+		case <-ctx.Done():
+			stop()
+			return
+
+		case <-sst.overrides:
+			// the overrides changed, so recompute the next change
+			stop()
+
+		case <-timeCh:
+			sst.refreshOverride(ctx)
+		}
+	}
+}
+
+// refreshOverride recomputes this Monitor's state so that the current override for
+// this subsystem takes effect. If the given context has been canceled, this method
+// does nothing.
+func (sst *subsystemTracker) refreshOverride(ctx context.Context) {
+	defer sst.lock.Unlock()
+	sst.lock.Lock()
+
+	if sst.removed || ctx.Err() != nil {
+		return
+	}
+
+	sst.unsafeUpdateState(sst.now().UTC(), sst.definition.Name)
+}
+
+// unsafeOverridesChanged signals this subsystem's override task, starting tasks if
+// necessary, and recomputes this Monitor's state.
+//
+// This method must be executed under the monitor lock.
+func (m *Monitor) unsafeOverridesChanged(sst *subsystemTracker) {
+	switch {
+	case sst.cancelTasks != nil:
+		// signal the running override task without blocking
+		select {
+		case sst.overrides <- struct{}{}:
+		default:
+		}
+
+	case m.ctx != nil:
+		sst.startTasks(m.ctx, nil)
+	}
+
+	m.unsafeUpdateState(m.now().UTC(), sst.definition.Name)
+}
+
+// setOverride replaces the manual override for a subsystem.
+func (m *Monitor) setOverride(n Name, o *Override) error {
+	defer m.lock.Unlock()
+	m.lock.Lock()
+
+	sst, err := m.find(n)
+	if err != nil {
+		return err
+	}
+
+	sst.override = o
+	m.unsafeOverridesChanged(sst)
+	return nil
+}
+
+// Override forces the effective status of the given subsystem until the given time, or
+// until cleared if until is the zero time. Updates and probe results are still recorded
+// as the subsystem's observed status. A manual override takes precedence over any
+// maintenance window and replaces any previous manual override.
+//
+// If no such subsystem exists, this method returns an error that wraps ErrNoSuchSubsystem.
+func (m *Monitor) Override(n Name, s Status, reason string, until time.Time) error {
+	return m.setOverride(n, &Override{
+		Status: s,
+		Reason: reason,
+		Until:  until.UTC(),
+	})
+}
+
+// Exclude is like Override, but excludes the given subsystem from aggregation rather
+// than forcing its status. An excluded subsystem does not affect the overall status,
+// its group, or its dependents, much like a paused subsystem.
+//
+// If no such subsystem exists, this method returns an error that wraps ErrNoSuchSubsystem.
+func (m *Monitor) Exclude(n Name, reason string, until time.Time) error {
+	return m.setOverride(n, &Override{
+		Exclude: true,
+		Reason:  reason,
+		Until:   until.UTC(),
+	})
+}
+
+// ClearOverride removes any manual override for the given subsystem, set via either
+// Override or Exclude. Maintenance windows are not affected.
+//
+// If no such subsystem exists, this method returns an error that wraps ErrNoSuchSubsystem.
+func (m *Monitor) ClearOverride(n Name) error {
+	return m.setOverride(n, nil)
+}
+
+// ScheduleMaintenance adds a maintenance window for the given subsystem. The window
+// takes effect when it starts, or immediately if it has already started.
+//
+// If no such subsystem exists, this method returns an error that wraps ErrNoSuchSubsystem.
+func (m *Monitor) ScheduleMaintenance(n Name, w MaintenanceWindow) error {
+	if err := validateMaintenance(n, w); err != nil {
+		return err
+	}
+
+	defer m.lock.Unlock()
+	m.lock.Lock()
+
+	sst, err := m.find(n)
+	if err != nil {
+		return err
+	}
+
+	sst.windows = append(sst.windows, w)
+	m.unsafeOverridesChanged(sst)
+	return nil
+}
@@ -5,6 +5,7 @@ package haelu
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"time"
 )
@@ -16,9 +17,18 @@ const (
 	DefaultProbeInterval time.Duration = 2 * time.Minute
 )
 
+// ErrProbeTimeout indicates that a Probe did not return a result within
+// its configured timeout. The errors recorded for timed out probes will
+// wrap this error and have StatusBad associated with them.
+var ErrProbeTimeout = errors.New("the probe timed out")
+
+// ErrNoProbe indicates that a subsystem has no Probe to run.
+var ErrNoProbe = errors.New("the subsystem has no probe")
+
 // Probe is a callback type to interrogate a subsystem for its health status.
 // A Probe may consult information out-of-process, so it's passed a context.Context
-// that gets canceled when a Monitor is shutdown.
+// that gets canceled when a Monitor is shutdown or when the probe's timeout, if any,
+// expires.
 type Probe func(context.Context) (Status, error)
 
 // ProbeFunc describes the various closure types that are convertible to Probes.
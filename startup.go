@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"context"
+	"time"
+)
+
+// initializeStartup applies the default startup grace period for this subsystem.
+func (sst *subsystemTracker) initializeStartup(defaultGracePeriod time.Duration) {
+	if sst.definition.StartupGracePeriod == 0 {
+		sst.definition.StartupGracePeriod = defaultGracePeriod
+	}
+}
+
+// unsafeBeginStartup starts this subsystem's startup grace period, if it has one and
+// the grace period hasn't already begun. A subsystem's grace period begins only once,
+// the first time its tasks are started.
+//
+// This method must be executed under the monitor lock.
+func (sst *subsystemTracker) unsafeBeginStartup() {
+	if sst.definition.StartupGracePeriod > 0 && sst.startupEnds.IsZero() {
+		sst.startupEnds = sst.now().Add(sst.definition.StartupGracePeriod)
+		sst.current.Starting = true
+	}
+}
+
+// unsafeCheckStartup ends this subsystem's startup grace period early once it
+// has observed StatusGood.
+//
+// This method must be executed under the monitor lock.
+func (sst *subsystemTracker) unsafeCheckStartup() {
+	if sst.current.Starting && sst.current.ObservedStatus == StatusGood {
+		sst.current.Starting = false
+	}
+}
+
+// unsafeApplyStartup caps this subsystem's effective status at its StartupStatus
+// during its startup grace period.
+//
+// This method must be executed under the monitor lock.
+func (sst *subsystemTracker) unsafeApplyStartup() {
+	if sst.current.Starting {
+		sst.current.Status = min(sst.current.Status, sst.definition.StartupStatus)
+	}
+}
+
+// startupTask ends this subsystem's startup grace period once the given amount of
+// time has elapsed, unless the given context is canceled first.
+func (sst *subsystemTracker) startupTask(ctx context.Context, remaining time.Duration) {
+	timeCh, stop := sst.newTimer(remaining)
+	defer stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timeCh:
+		sst.endStartup(ctx)
+	}
+}
+
+// endStartup ends this subsystem's startup grace period, if it is still in effect.
+// If the given context has been canceled, this method does nothing.
+func (sst *subsystemTracker) endStartup(ctx context.Context) {
+	defer sst.lock.Unlock()
+	sst.lock.Lock()
+
+	if sst.removed || ctx.Err() != nil || !sst.current.Starting {
+		return
+	}
+
+	sst.current.Starting = false
+	sst.unsafeUpdateState(sst.now().UTC(), sst.definition.Name)
+}
+
+// WithDefaultStartupGracePeriod sets the default startup grace period for subsystems
+// that do not define their own StartupGracePeriod. If unset or nonpositive, subsystems
+// have no startup grace period by default.
+func WithDefaultStartupGracePeriod(d time.Duration) MonitorOption {
+	return monitorOptionFunc(func(m *Monitor) error {
+		m.defaultStartupGracePeriod = max(d, 0)
+		return nil
+	})
+}
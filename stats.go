@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import "time"
+
+// Stats are uptime and availability statistics for a single subsystem. Statistics
+// are based on the subsystem's effective status and are collected from the time
+// its Monitor is first started, or from the time the subsystem is added if that
+// is later.
+type Stats struct {
+	// Since is the UTC time at which statistics collection began. This will be
+	// the zero time if the Monitor has never been started.
+	Since time.Time `json:"since" yaml:"since"`
+
+	// TimeInStatus is the total amount of time spent in each status.
+	TimeInStatus map[Status]time.Duration `json:"timeInStatus" yaml:"timeInStatus"`
+
+	// Transitions is the number of changes in status.
+	Transitions int `json:"transitions" yaml:"transitions"`
+
+	// LastTransition is the UTC time of the most recent change in status. This will
+	// be the zero time if there have been no transitions.
+	LastTransition time.Time `json:"lastTransition" yaml:"lastTransition"`
+
+	// Recoveries is the number of times the subsystem returned to StatusGood.
+	Recoveries int `json:"recoveries" yaml:"recoveries"`
+
+	// MeanTimeToRecover is the average amount of time the subsystem spent away from
+	// StatusGood before recovering. Outages that are still ongoing are not included.
+	MeanTimeToRecover time.Duration `json:"meanTimeToRecover" yaml:"meanTimeToRecover"`
+
+	// Availability is the percentage of time, from 0 to 100, the subsystem spent
+	// in StatusGood.
+	Availability float64 `json:"availability" yaml:"availability"`
+}
+
+// statsTracker accumulates statistics for a single subsystem.
+type statsTracker struct {
+	// since is the time collection began. If zero, collection hasn't started.
+	since time.Time
+
+	// status is the status as of the most recent observation.
+	status Status
+
+	// changed is the time at which status was first observed.
+	changed time.Time
+
+	timeInStatus   map[Status]time.Duration
+	transitions    int
+	lastTransition time.Time
+
+	// outage is the time at which the subsystem left StatusGood, or the zero
+	// time if the subsystem is currently StatusGood.
+	outage time.Time
+
+	recoveries int
+	downtime   time.Duration
+}
+
+// observe records the status of the subsystem at the given time. If collection has
+// not begun, it begins now only if start is true.
+func (st *statsTracker) observe(timestamp time.Time, s Status, start bool) {
+	switch {
+	case st.since.IsZero() && !start:
+		return
+
+	case st.since.IsZero():
+		st.since = timestamp
+		st.status = s
+		st.changed = timestamp
+		st.timeInStatus = make(map[Status]time.Duration)
+		if s != StatusGood {
+			st.outage = timestamp
+		}
+
+		return
+
+	case s == st.status:
+		return
+	}
+
+	st.timeInStatus[st.status] += max(timestamp.Sub(st.changed), 0)
+	st.transitions++
+	st.lastTransition = timestamp
+
+	switch {
+	case s == StatusGood:
+		st.recoveries++
+		st.downtime += max(timestamp.Sub(st.outage), 0)
+		st.outage = time.Time{}
+
+	case st.status == StatusGood:
+		st.outage = timestamp
+	}
+
+	st.status = s
+	st.changed = timestamp
+}
+
+// snapshot computes the statistics as of the given time.
+func (st *statsTracker) snapshot(now time.Time) (s Stats) {
+	s = Stats{
+		Since:          st.since,
+		TimeInStatus:   make(map[Status]time.Duration, len(st.timeInStatus)+1),
+		Transitions:    st.transitions,
+		LastTransition: st.lastTransition,
+		Recoveries:     st.recoveries,
+	}
+
+	if st.since.IsZero() {
+		return
+	}
+
+	for status, d := range st.timeInStatus {
+		s.TimeInStatus[status] = d
+	}
+
+	// include the time spent in the current status
+	s.TimeInStatus[st.status] += max(now.Sub(st.changed), 0)
+
+	if st.recoveries > 0 {
+		s.MeanTimeToRecover = st.downtime / time.Duration(st.recoveries)
+	}
+
+	switch total := max(now.Sub(st.since), 0); {
+	case total > 0:
+		s.Availability = 100 * float64(s.TimeInStatus[StatusGood]) / float64(total)
+
+	case st.status == StatusGood:
+		s.Availability = 100
+	}
+
+	return
+}
+
+// Stats returns the uptime and availability statistics for the given subsystem,
+// computed as of the current time.
+//
+// If no such subsystem exists, this method returns an error that wraps ErrNoSuchSubsystem.
+func (m *Monitor) Stats(n Name) (Stats, error) {
+	defer m.lock.Unlock()
+	m.lock.Lock()
+
+	sst, err := m.find(n)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	return sst.stats.snapshot(m.now().UTC()), nil
+}
+
+// allStats returns the uptime and availability statistics for every subsystem,
+// computed as of the current time.
+func (m *Monitor) allStats() map[Name]Stats {
+	defer m.lock.Unlock()
+	m.lock.Lock()
+
+	now := m.now().UTC()
+	s := make(map[Name]Stats, len(m.trackers))
+	for _, sst := range m.trackers {
+		s[sst.definition.Name] = sst.stats.snapshot(now)
+	}
+
+	return s
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type StatsTestSuite struct {
+	suite.Suite
+
+	start time.Time
+}
+
+func (suite *StatsTestSuite) SetupTest() {
+	suite.start = time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+}
+
+// at returns the time the given number of seconds after the start.
+func (suite *StatsTestSuite) at(seconds int) time.Time {
+	return suite.start.Add(time.Duration(seconds) * time.Second)
+}
+
+func (suite *StatsTestSuite) TestNotStarted() {
+	var st statsTracker
+	st.observe(suite.at(0), StatusBad, false)
+	suite.Equal(
+		Stats{
+			TimeInStatus: map[Status]time.Duration{},
+		},
+		st.snapshot(suite.at(10)),
+	)
+}
+
+func (suite *StatsTestSuite) TestNoElapsedTime() {
+	var good, bad statsTracker
+	good.observe(suite.at(0), StatusGood, true)
+	bad.observe(suite.at(0), StatusBad, true)
+
+	suite.Equal(100.0, good.snapshot(suite.at(0)).Availability)
+	suite.Zero(bad.snapshot(suite.at(0)).Availability)
+}
+
+func (suite *StatsTestSuite) TestTransitions() {
+	var st statsTracker
+	st.observe(suite.at(0), StatusGood, true)
+	st.observe(suite.at(0), StatusGood, false) // only the first observation needs start
+	st.observe(suite.at(10), StatusWarn, false)
+	st.observe(suite.at(20), StatusBad, false)
+	st.observe(suite.at(30), StatusGood, false)
+	st.observe(suite.at(60), StatusBad, false)
+	st.observe(suite.at(70), StatusBad, false)
+	st.observe(suite.at(70), StatusGood, false)
+	st.observe(suite.at(80), StatusBad, false)
+
+	suite.Equal(
+		Stats{
+			Since: suite.at(0),
+			TimeInStatus: map[Status]time.Duration{
+				StatusGood: 50 * time.Second,
+				StatusWarn: 10 * time.Second,
+				StatusBad:  40 * time.Second,
+			},
+			Transitions:       6,
+			LastTransition:    suite.at(80),
+			Recoveries:        2,
+			MeanTimeToRecover: 15 * time.Second,
+			Availability:      50.0,
+		},
+		st.snapshot(suite.at(100)),
+	)
+}
+
+func TestStats(t *testing.T) {
+	suite.Run(t, new(StatsTestSuite))
+}
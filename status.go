@@ -3,6 +3,8 @@
 
 package haelu
 
+import "fmt"
+
 //go:generate stringer -type=Status -linecomment
 
 // Status indicates the health status of a single subsystem or the overall application.
@@ -23,3 +25,20 @@ const (
 func (s Status) MarshalText() ([]byte, error) {
 	return []byte(s.String()), nil
 }
+
+// ParseStatus parses the string value of a Status, as produced by String.
+func ParseStatus(v string) (Status, error) {
+	for _, s := range []Status{StatusGood, StatusWarn, StatusBad} {
+		if v == s.String() {
+			return s, nil
+		}
+	}
+
+	return StatusGood, fmt.Errorf("invalid status: %q", v)
+}
+
+// UnmarshalText parses the string value of a Status.
+func (s *Status) UnmarshalText(text []byte) (err error) {
+	*s, err = ParseStatus(string(text))
+	return
+}
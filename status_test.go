@@ -22,6 +22,31 @@ func (suite *StatusTestSuite) TestDistinctStrings() {
 	suite.Len(m, 3)
 }
 
+func (suite *StatusTestSuite) TestParseStatus() {
+	for _, expected := range []Status{StatusGood, StatusWarn, StatusBad} {
+		suite.Run(expected.String(), func() {
+			actual, err := ParseStatus(expected.String())
+			suite.NoError(err)
+			suite.Equal(expected, actual)
+
+			text, err := expected.MarshalText()
+			suite.Require().NoError(err)
+
+			var unmarshaled Status
+			suite.NoError(unmarshaled.UnmarshalText(text))
+			suite.Equal(expected, unmarshaled)
+		})
+	}
+
+	suite.Run("Invalid", func() {
+		_, err := ParseStatus("nosuch")
+		suite.Error(err)
+
+		var s Status
+		suite.Error(s.UnmarshalText([]byte("nosuch")))
+	})
+}
+
 func TestStatus(t *testing.T) {
 	suite.Run(t, new(StatusTestSuite))
 }
@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: 2025 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package haelu
+
+import (
+	"slices"
+	"sync"
+)
+
+// DefaultEventBuffer is the default number of events that can be queued for a
+// callback subscriber before events are dropped.
+const DefaultEventBuffer = 16
+
+// Event describes an update to a Monitor's state.
+type Event struct {
+	// Previous is the overall status of the Monitor prior to the update.
+	Previous Status
+
+	// Current is the overall status of the Monitor after the update.
+	Current Status
+
+	// Trigger is the name of the subsystem that caused the update. This will be
+	// empty if the update was not caused by a single subsystem, such as when a
+	// Monitor is started.
+	Trigger Name
+
+	// State is the Monitor's state after the update.
+	State MonitorState
+}
+
+// Transition tests if this event represents a change in the overall status.
+func (e Event) Transition() bool {
+	return e.Previous != e.Current
+}
+
+// SubscribeOption represents a configurable option for a subscription.
+type SubscribeOption interface {
+	apply(*subscription)
+}
+
+type subscribeOptionFunc func(*subscription)
+
+func (f subscribeOptionFunc) apply(s *subscription) { f(s) }
+
+// WithTransitionsOnly restricts a subscription to events where the overall
+// status changed. By default, a subscription receives an event for every update.
+func WithTransitionsOnly() SubscribeOption {
+	return subscribeOptionFunc(func(s *subscription) {
+		s.transitionsOnly = true
+	})
+}
+
+// WithEventBuffer sets the number of events that can be queued for a callback
+// subscriber. If unset or nonpositive, DefaultEventBuffer is used. This option
+// has no effect on channel subscribers, which are buffered by the caller.
+func WithEventBuffer(n int) SubscribeOption {
+	return subscribeOptionFunc(func(s *subscription) {
+		s.buffer = n
+	})
+}
+
+// subscription is a single registered listener for Monitor events.
+type subscription struct {
+	transitionsOnly bool
+	buffer          int
+	events          chan<- Event
+}
+
+// deliver sends an event to this subscription without blocking. If the subscriber
+// cannot accept the event, it is dropped.
+func (s *subscription) deliver(e Event) {
+	if s.transitionsOnly && !e.Transition() {
+		return
+	}
+
+	select {
+	case s.events <- e:
+	default:
+	}
+}
+
+// subscribers holds the set of subscriptions for a Monitor.
+//
+// This type has its own lock so that subscribing and cancelling never contend
+// with the monitor lock. Events are always published while holding this lock,
+// which guarantees that no event is sent to a subscription after it is cancelled.
+type subscribers struct {
+	lock sync.Mutex
+	subs []*subscription
+}
+
+// add registers a subscription.
+func (ss *subscribers) add(s *subscription) {
+	defer ss.lock.Unlock()
+	ss.lock.Lock()
+	ss.subs = append(ss.subs, s)
+}
+
+// remove deregisters a subscription.
+func (ss *subscribers) remove(s *subscription) {
+	defer ss.lock.Unlock()
+	ss.lock.Lock()
+	ss.subs = slices.DeleteFunc(ss.subs, func(candidate *subscription) bool {
+		return candidate == s
+	})
+}
+
+// publish delivers an event to each subscription.
+func (ss *subscribers) publish(e Event) {
+	defer ss.lock.Unlock()
+	ss.lock.Lock()
+	for _, s := range ss.subs {
+		s.deliver(e)
+	}
+}
+
+// changes broadcasts state updates to any number of waiters by closing a channel.
+//
+// Like subscribers, this type has its own lock so that waiting never contends with
+// the monitor lock.
+type changes struct {
+	lock sync.Mutex
+	ch   chan struct{}
+}
+
+// wait returns the channel that will be closed upon the next broadcast.
+func (c *changes) wait() <-chan struct{} {
+	defer c.lock.Unlock()
+	c.lock.Lock()
+	if c.ch == nil {
+		c.ch = make(chan struct{})
+	}
+
+	return c.ch
+}
+
+// broadcast wakes up all current waiters.
+func (c *changes) broadcast() {
+	defer c.lock.Unlock()
+	c.lock.Lock()
+	if c.ch != nil {
+		close(c.ch)
+		c.ch = nil
+	}
+}
+
+// newSubscription applies options to create a subscription.
+func newSubscription(opts []SubscribeOption) *subscription {
+	s := new(subscription)
+	for _, o := range opts {
+		o.apply(s)
+	}
+
+	return s
+}
+
+// Subscribe registers a callback that receives an Event each time this Monitor's
+// state is updated. The callback is invoked on a separate goroutine, one event at a
+// time, in the order the updates occurred.
+//
+// Delivery never blocks updates to this Monitor. If the callback falls behind by more
+// than the subscription's buffer, events are dropped.
+//
+// The returned closure cancels the subscription. It is idempotent. After it returns,
+// no new events will be queued for the callback, though an event that is already
+// being processed may still be running.
+func (m *Monitor) Subscribe(f func(Event), opts ...SubscribeOption) (cancel func()) {
+	s := newSubscription(opts)
+	if s.buffer < 1 {
+		s.buffer = DefaultEventBuffer
+	}
+
+	events := make(chan Event, s.buffer)
+	s.events = events
+	go func() {
+		for e := range events {
+			f(e)
+		}
+	}()
+
+	m.subscribers.add(s)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.subscribers.remove(s)
+			close(events)
+		})
+	}
+}
+
+// SubscribeChan registers a channel that receives an Event each time this Monitor's
+// state is updated. Delivery never blocks updates to this Monitor: if the channel
+// cannot accept an event immediately, that event is dropped. Callers should buffer
+// the channel appropriately.
+//
+// The returned closure cancels the subscription. It is idempotent and does not
+// close the channel. After it returns, no further events will be sent on the channel.
+func (m *Monitor) SubscribeChan(ch chan<- Event, opts ...SubscribeOption) (cancel func()) {
+	s := newSubscription(opts)
+	s.events = ch
+	m.subscribers.add(s)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.subscribers.remove(s)
+		})
+	}
+}
+
+// Changed returns a channel that is closed the next time this Monitor's state is
+// updated. Unlike a subscription, this requires no cleanup and is suitable for
+// many short-lived waiters, such as long-polling HTTP requests.
+//
+// To avoid missing an update, callers should obtain this channel before examining
+// the State.
+func (m *Monitor) Changed() <-chan struct{} {
+	return m.changes.wait()
+}
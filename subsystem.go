@@ -10,7 +10,8 @@ import (
 )
 
 // Updater is a interface that can be used to update a subsystem's
-// health Status as well as pause and resume monitoring.
+// health Status. Monitoring of a subsystem can be paused and resumed
+// via Monitor.Pause and Monitor.Resume.
 type Updater interface {
 	// Update supplies a possibly new status and an optional error that
 	// occurred while checking the status or otherwise using the subsystem.
@@ -32,8 +33,20 @@ type Definition struct {
 	// will have effect until the first time an update occurs.
 	Status Status
 
+	// Group is the optional name of the group this subsystem belongs to. The group
+	// must be defined for the Monitor, e.g. via WithGroups. A grouped subsystem affects
+	// the overall Monitor status only through its group's status.
+	Group Name
+
+	// Kinds are the views of overall health this subsystem participates in, such as
+	// KindLiveness or KindReadiness. The Monitor computes a separate overall status for
+	// each kind. If empty, this subsystem participates in every kind.
+	Kinds []Kind
+
 	// NonCritical indicates how this subsystem affects the overall Monitor status. By
-	// default, this field is false, which means that a subsystem is critical.
+	// default, this field is false, which means that a subsystem is critical. The rules
+	// below describe the default Aggregator. Other Aggregators may treat this field
+	// differently.
 	//
 	// A critical subsystem directly affects a Monitor's overall status. If any critical
 	// subsystems are StatusWarn, the overall status will be StatusWarn. If any critical
@@ -43,6 +56,44 @@ type Definition struct {
 	// subsystem is NOT StatusGood, the overall status will be StatusWarn.
 	NonCritical bool
 
+	// FailureThreshold is the number of consecutive updates that must agree on a worse
+	// status before this subsystem's status changes to that status. This applies to both
+	// Probe results and updates via an Updater. Values less than or equal to 1 mean that
+	// a worse status takes effect immediately.
+	FailureThreshold int
+
+	// SuccessThreshold is the number of consecutive updates that must agree on a better
+	// status before this subsystem's status changes to that status. This applies to both
+	// Probe results and updates via an Updater. Values less than or equal to 1 mean that
+	// a better status takes effect immediately.
+	SuccessThreshold int
+
+	// DependsOn are the names of other subsystems within the same Monitor that this subsystem
+	// depends on. Whenever a dependency is StatusBad, this subsystem is also StatusBad and its
+	// snapshot describes the root cause via a DependencyError. Once its dependencies recover,
+	// this subsystem reverts to its own observed status. Paused dependencies are ignored.
+	//
+	// A StatusBad dependency makes this subsystem StatusBad even during its startup grace
+	// period, as that period only excuses this subsystem's own failures. A dependency that
+	// is itself within its grace period contributes its capped status.
+	//
+	// Dependencies must exist and must not form a cycle.
+	DependsOn []Name
+
+	// SuppressProbes indicates that this subsystem's Probe should not be run while it is
+	// StatusBad because of a dependency. This field is ignored if no Probe is set.
+	SuppressProbes bool
+
+	// Monitor is an optional child Monitor that this subsystem tracks. The subsystem's
+	// status follows the child's overall status as the child is updated, and the child's
+	// state is embedded in this subsystem's snapshot. The child's lifecycle, i.e. Start
+	// and Shutdown, is independent of the Monitor that contains this subsystem.
+	//
+	// A subsystem cannot have both a Monitor and a Probe, and Monitors cannot be nested
+	// in a cycle. When a Monitor is set, this subsystem's initial status is taken from
+	// the child rather than the Status field.
+	Monitor *Monitor
+
 	// Probe is an optional closure that interrogates this subsystem's state. A probe will be
 	// called only if both (1) the Monitor has been started, and (2) the subsystem is not paused.
 	//
@@ -53,6 +104,134 @@ type Definition struct {
 	// this field is ignored.
 	ProbeInterval time.Duration
 
+	// WarnProbeInterval is the interval used after a Probe reports StatusWarn. This allows
+	// a subsystem to be probed more or less frequently while it is having problems. If unset
+	// or nonpositive, ProbeInterval is used.
+	WarnProbeInterval time.Duration
+
+	// BadProbeInterval is the interval used after a Probe reports StatusBad. This allows
+	// a subsystem to be probed more or less frequently while it is unusable. If unset
+	// or nonpositive, ProbeInterval is used.
+	BadProbeInterval time.Duration
+
+	// ProbeBackoff is the multiplier applied to the interval for each consecutive Probe
+	// result that is not StatusGood, after the first. For example, a value of 2 doubles
+	// the interval each time a Probe fails again. The interval returns to normal as soon as
+	// a Probe reports StatusGood.
+	//
+	// Values less than or equal to 1 disable backoff.
+	ProbeBackoff float64
+
+	// MaxProbeInterval caps the interval computed from ProbeBackoff. If unset or nonpositive,
+	// the backoff interval is not capped.
+	MaxProbeInterval time.Duration
+
+	// ProbeImmediately indicates that the Probe should be invoked as soon as its probe task
+	// starts, rather than after the first ProbeInterval has elapsed. Probe tasks start when
+	// the Monitor is started, when a subsystem is added to a running Monitor, and when a
+	// subsystem is resumed.
+	//
+	// A Monitor may be configured to probe all subsystems immediately, in which case this
+	// field is ignored. If no Probe is set, this field is ignored.
+	ProbeImmediately bool
+
+	// ProbeJitter is the maximum random amount of time added to each ProbeInterval. Jitter
+	// prevents subsystems that share an interval from probing in lock-step. The actual
+	// delay between probes will be in the range [ProbeInterval, ProbeInterval+ProbeJitter).
+	//
+	// If this field is positive, it takes precedence over ProbeJitterFactor. If neither
+	// jitter field is set, the Monitor's defaults are used.
+	ProbeJitter time.Duration
+
+	// ProbeJitterFactor expresses the maximum jitter as a fraction of the ProbeInterval. For
+	// example, a value of 0.1 means that up to 10% of the ProbeInterval will be randomly
+	// added to each delay between probes.
+	//
+	// This field is ignored if ProbeJitter is positive.
+	ProbeJitterFactor float64
+
+	// ProbeStagger is the maximum random amount of time added to the delay before the first
+	// Probe. This spreads out the initial probes for subsystems that start together. This
+	// field is ignored when the subsystem probes immediately.
+	//
+	// If unset or nonpositive, the Monitor's default stagger is used.
+	ProbeStagger time.Duration
+
+	// ProbeTimeout is the maximum amount of time a single Probe invocation may take. The
+	// context passed to the Probe will have a deadline based on this timeout. If the Probe
+	// does not return in time, the subsystem is updated with StatusBad and an error that
+	// wraps ErrProbeTimeout.
+	//
+	// If unset or nonpositive, the Monitor's default probe timeout is used. If no Probe is
+	// set, this field is ignored.
+	ProbeTimeout time.Duration
+
+	// TTL is the maximum amount of time allowed between updates to this subsystem while
+	// the Monitor is running. Updates from both an Updater and a Probe count. If no update
+	// arrives within the TTL, the subsystem is changed to StaleStatus with an error that
+	// wraps ErrSubsystemStale. Paused subsystems are never marked stale.
+	//
+	// If unset or nonpositive, this subsystem never goes stale.
+	TTL time.Duration
+
+	// StaleStatus is the status a subsystem is changed to when its TTL elapses. If unset,
+	// i.e. StatusGood, StatusBad is used. This field is ignored if TTL is not set.
+	StaleStatus Status
+
+	// HistorySize is the maximum number of transitions in observed status or error
+	// retained for this subsystem. Once full, the oldest transitions are discarded.
+	// History is available via Monitor.History.
+	//
+	// If unset, the Monitor's default history size is used. If negative, no history
+	// is kept for this subsystem.
+	HistorySize int
+
+	// FlapThreshold is the number of transitions in observed status within FlapWindow
+	// that marks this subsystem as flapping. Once a subsystem changes status more than
+	// this many times within the window, its effective status becomes FlappingStatus until
+	// no transitions occur for FlapQuietPeriod. Thresholds are applied before transitions
+	// are counted.
+	//
+	// If unset or nonpositive, flapping is not detected for this subsystem.
+	FlapThreshold int
+
+	// FlapWindow is the sliding window in which transitions are counted. If unset or
+	// nonpositive, DefaultFlapWindow is used. This field is ignored if FlapThreshold
+	// is not set.
+	FlapWindow time.Duration
+
+	// FlapQuietPeriod is the amount of time a flapping subsystem must go without a
+	// transition before it is no longer considered flapping. If unset or nonpositive,
+	// FlapWindow is used. This field is ignored if FlapThreshold is not set.
+	FlapQuietPeriod time.Duration
+
+	// FlappingStatus is the effective status of this subsystem while it is flapping.
+	// If unset, i.e. StatusGood, StatusWarn is used. This field is ignored if
+	// FlapThreshold is not set.
+	FlappingStatus Status
+
+	// StartupGracePeriod is the amount of time, beginning when the Monitor first runs this
+	// subsystem's tasks, during which this subsystem's effective status is capped at
+	// StartupStatus. Updates, including probe results, are still recorded as the observed
+	// status. The grace period ends early the first time this subsystem observes StatusGood.
+	//
+	// The cap applies only to this subsystem's own observed status. A StatusBad dependency
+	// still makes this subsystem StatusBad during its grace period. See DependsOn.
+	//
+	// If unset, the Monitor's default startup grace period is used. If negative, this
+	// subsystem has no startup grace period.
+	StartupGracePeriod time.Duration
+
+	// StartupStatus is the worst effective status this subsystem can have during its
+	// startup grace period. If unset, i.e. StatusGood, failures are ignored entirely
+	// during the grace period. This field is ignored if there is no grace period.
+	StartupStatus Status
+
+	// Maintenance are the scheduled maintenance windows for this subsystem. During a
+	// window, this subsystem's effective status is forced or it is excluded from aggregation,
+	// as described by the window. More windows may be added via Monitor.ScheduleMaintenance.
+	Maintenance []MaintenanceWindow
+
 	// Metadata are optional name/value pairs to associate with this subsystem. A caller may
 	// specify any values in this map to act as metadata for the subsystem.
 	Metadata Metadata
@@ -63,10 +242,19 @@ type Subsystem struct {
 	// Name is the unique identifier for this subsystem.
 	Name Name `json:"name" yaml:"name"`
 
-	// Status is the current status of this subsystem. When creating a
+	// Status is the current, effective status of this subsystem. When creating a
 	// Monitor, this is the initial status.
 	Status Status `json:"status" yaml:"status"`
 
+	// ObservedStatus is the status this subsystem reports on its own, i.e. from its
+	// updates, before dependencies, flapping, a startup grace period, or overrides are
+	// taken into account.
+	ObservedStatus Status `json:"observedStatus" yaml:"observedStatus"`
+
+	// DependencyError is set when this subsystem is StatusBad because one of its
+	// dependencies is StatusBad. It describes the root cause of the failure.
+	DependencyError *DependencyError `json:"dependencyError,omitempty" yaml:"dependencyError,omitempty"`
+
 	// LastUpdate is the UTC timestamp of the last status update to this subsystem.
 	// This field is set to the current time upon creation. Only status updates
 	// affect this timestamp. Pausing or disabling a subsystem does not update
@@ -81,9 +269,57 @@ type Subsystem struct {
 	// the overall Monitor status.
 	NonCritical bool `json:"nonCritical" yaml:"nonCritical"`
 
+	// Group is the name of the group this subsystem belongs to, if any.
+	Group Name `json:"group,omitempty" yaml:"group,omitempty"`
+
+	// Kinds are the views of overall health this subsystem participates in. If empty,
+	// this subsystem participates in every kind.
+	Kinds []Kind `json:"kinds,omitempty" yaml:"kinds,omitempty"`
+
 	// Metadata is the optional set of name/value pairs that were supplied when the
 	// subsystem was defined.
 	Metadata Metadata `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// Monitor is the state of the child Monitor this subsystem tracks, if any.
+	Monitor *MonitorState `json:"monitor,omitempty" yaml:"monitor,omitempty"`
+
+	// Pending describes a status change that has not yet been committed because the
+	// subsystem's failure or success threshold has not been reached. This field is
+	// nil when there is no pending status change.
+	Pending *Pending `json:"pending,omitempty" yaml:"pending,omitempty"`
+
+	// Paused indicates whether monitoring of this subsystem has been paused. A paused
+	// subsystem does not contribute to the overall Monitor status.
+	Paused bool `json:"paused" yaml:"paused"`
+
+	// Flapping indicates whether this subsystem is changing status too frequently. While
+	// flapping, the effective status of this subsystem is its configured flapping status
+	// rather than its observed status.
+	Flapping bool `json:"flapping" yaml:"flapping"`
+
+	// Starting indicates whether this subsystem is in its startup grace period. While
+	// starting, the effective status of this subsystem is capped at its configured
+	// startup status.
+	Starting bool `json:"starting" yaml:"starting"`
+
+	// Override describes the manual override or maintenance window currently in effect
+	// for this subsystem, if any. This field is nil when no override is in effect.
+	Override *Override `json:"override,omitempty" yaml:"override,omitempty"`
+}
+
+// Pending describes a status change for a subsystem that is waiting on enough
+// consecutive updates to reach its threshold.
+type Pending struct {
+	// Status is the status that the subsystem will change to once the
+	// threshold is reached.
+	Status Status `json:"status" yaml:"status"`
+
+	// Count is the number of consecutive updates that have reported Status.
+	Count int `json:"count" yaml:"count"`
+
+	// Threshold is the number of consecutive updates required to commit
+	// the status change.
+	Threshold int `json:"threshold" yaml:"threshold"`
 }
 
 // Subsystems is an immutable, iterable sequence of Subsystem snapshots.
@@ -130,7 +366,23 @@ func (s Subsystems) All() iter.Seq[Subsystem] {
 	}
 }
 
+// find locates the Subsystem with the given name.
+func (s Subsystems) find(n Name) (Subsystem, bool) {
+	for _, candidate := range s.ss {
+		if candidate.Name == n {
+			return candidate, true
+		}
+	}
+
+	return Subsystem{}, false
+}
+
 // MarshalJSON marshals this sequence as a slice of Subsystems.
 func (s Subsystems) MarshalJSON() ([]byte, error) {
 	return json.Marshal(s.ss)
 }
+
+// MarshalYAML marshals this sequence as a slice of Subsystems.
+func (s Subsystems) MarshalYAML() (any, error) {
+	return s.ss, nil
+}
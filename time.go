@@ -3,7 +3,10 @@
 
 package haelu
 
-import "time"
+import (
+	"math/rand/v2"
+	"time"
+)
 
 // now is a closure used to produce the current time.
 // By default, time.Now is used.
@@ -18,3 +21,13 @@ func defaultNewTimer(d time.Duration) (<-chan time.Time, func() bool) {
 	t := time.NewTimer(d)
 	return t.C, t.Stop
 }
+
+// random is a closure that produces a pseudo-random duration in the half-open
+// interval [0, d). The d passed to this closure will always be positive.
+type random func(d time.Duration) time.Duration
+
+// defaultRandom is the default random closure, which uses the top-level
+// functions in math/rand/v2.
+func defaultRandom(d time.Duration) time.Duration {
+	return rand.N(d)
+}
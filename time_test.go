@@ -4,8 +4,10 @@
 package haelu
 
 import (
+	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/xmidt-org/chronon"
 )
 
@@ -17,3 +19,23 @@ func fakeTimer(fc *chronon.FakeClock) newTimer {
 		return ft.C(), ft.Stop
 	}
 }
+
+// notifyingFakeTimer is like fakeTimer, but also sends the duration of each
+// created timer on the supplied channel. Tests can use this to wait until a
+// background task has created its timer before advancing the FakeClock.
+func notifyingFakeTimer(fc *chronon.FakeClock, created chan<- time.Duration) newTimer {
+	nt := fakeTimer(fc)
+	return func(d time.Duration) (<-chan time.Time, func() bool) {
+		ch, stop := nt(d)
+		created <- d
+		return ch, stop
+	}
+}
+
+func TestDefaultRandom(t *testing.T) {
+	for range 100 {
+		d := defaultRandom(time.Second)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.Less(t, d, time.Second)
+	}
+}